@@ -7,20 +7,56 @@ package main
 
 import (
 	"context"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
+	"github.com/crossplane/uptest/v2/internal/cluster"
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
 	"github.com/crossplane/uptest/v2/pkg"
 )
 
+const (
+	// exitCodeInterrupted is the exit code of a run interrupted by a signal,
+	// which differs from the exit code of failed tests, i.e. 1.
+	exitCodeInterrupted = 130
+)
+
 var (
 	app = kingpin.New("uptest", "Automated Test Tool for Upbound Official Providers").DefaultEnvars()
 	// e2e command (single command is preserved for backward compatibility)
 	// and we may have further commands in the future.
 	e2e = app.Command("e2e", "Run e2e tests for manifests by applying them to a control plane and waiting until a given condition is met.")
+	// cleanup command deletes the objects left behind by a run.
+	cleanupCmd = app.Command("cleanup", "Delete the objects left behind by an uptest run, i.e. the objects labelled with its run ID.")
+	// gc command deletes the managed resources left behind by any run.
+	gcCmd = app.Command("gc", "Delete the managed resources left behind by uptest runs, e.g. killed CI jobs.")
+	// run command runs a test case rendered by a previous e2e run.
+	runCmd = app.Command("run", "Run the chainsaw test case already rendered in a test directory, or only one phase of it, against the current control plane.")
+)
+
+var (
+	logLevel  = app.Flag("log-level", "Minimum level of the logged messages, one of debug, info, warn or error.").Default("info").Enum("debug", "info", "warn", "error")
+	logFormat = app.Flag("log-format", "Format of the logged messages, text or json.").Default(string(logging.FormatText)).Enum(string(logging.FormatText), string(logging.FormatJSON))
+)
+
+var (
+	cleanupRunID       = cleanupCmd.Flag("run-id", "ID of the run whose objects will be deleted. The run ID is printed at the start of each run.").Required().String()
+	cleanupWaitTimeout = cleanupCmd.Flag("timeout", "Timeout of waiting for the objects to be deleted.").Default("600s").Duration()
+)
+
+var (
+	gcOlderThan = gcCmd.Flag("older-than", "Minimum age of the managed resources to delete.").Default("6h").Duration()
+	gcRunID     = gcCmd.Flag("run-id", "Only delete the managed resources of the run with the ID.").Default("").String()
+	gcGroups    = gcCmd.Flag("group", "Only delete the managed resources of the API group or its subgroups, e.g. aws.upbound.io. Can be repeated.").Strings()
+	gcDryRun    = gcCmd.Flag("dry-run", "Only print the deletion plan. Do not delete anything.").Default("false").Bool()
+	gcTimeout   = gcCmd.Flag("timeout", "Timeout of waiting for the managed resources to be deleted.").Default("1800s").Duration()
 )
 
 var (
@@ -35,29 +71,128 @@ var (
 
 	defaultTimeout = e2e.Flag("default-timeout", "Default timeout in seconds for the test.\n"+
 		"Timeout could be overridden per resource using \"uptest.upbound.io/timeout\" annotation.").Default("1200s").Duration()
+	applyTimeout = e2e.Flag("apply-timeout", "Timeout of the apply step. The timeout of the resources is used if not set.\n"+
+		"It could be overridden per resource using \"uptest.upbound.io/timeout-apply\" annotation.").Default("0s").Duration()
+	updateTimeout = e2e.Flag("update-timeout", "Timeout of the update step. The timeout of the resources is used if not set.\n"+
+		"It could be overridden per resource using \"uptest.upbound.io/timeout-update\" annotation.").Default("0s").Duration()
+	importTimeout = e2e.Flag("import-timeout", "Timeout of the import step. The timeout of the resources is used if not set.\n"+
+		"It could be overridden per resource using \"uptest.upbound.io/timeout-import\" annotation.").Default("0s").Duration()
+	deleteTimeout = e2e.Flag("delete-timeout", "Timeout of the delete step, which is independent of the time the previous steps took. The timeout of the resources is used if not set.\n"+
+		"It could be overridden per resource using \"uptest.upbound.io/timeout-delete\" annotation.").Default("0s").Duration()
 	defaultConditions = e2e.Flag("default-conditions", "Comma separated list of default conditions to wait for a successful test.\n"+
 		"Conditions could be overridden per resource using \"uptest.upbound.io/conditions\" annotation.").Default("Ready").String()
 
 	skipDelete               = e2e.Flag("skip-delete", "Skip the delete step of the test.").Default("false").Bool()
 	testDir                  = e2e.Flag("test-directory", "Directory where chainsaw test case will be generated and executed.").Envar("UPTEST_TEST_DIR").Default(filepath.Join(os.TempDir(), "uptest-e2e")).String()
 	onlyCleanUptestResources = e2e.Flag("only-clean-uptest-resources", "While deletion step, only clean resources that were created by uptest").Default("false").Bool()
+	waitAllManaged           = e2e.Flag("wait-all-managed", "At the end of the delete step, wait for all managed resources in the cluster to be deleted, instead of only the ones labelled with the run ID.").Default("false").Bool()
+	cleanupTimeout           = e2e.Flag("cleanup-timeout", "Timeout of waiting for the managed resources to be deleted at the end of the delete step.").Default("600s").Duration()
 
 	renderOnly         = e2e.Flag("render-only", "Only render test files. Do not run the tests.").Default("false").Bool()
-	logCollectInterval = e2e.Flag("log-collect-interval", "Specifies the interval duration for collecting logs. "+
+	logCollectInterval = e2e.Flag("log-collect-interval", "Specifies the interval duration for logging the summary of the tested resources. The summary is only logged if the resources changed. "+
 		"The duration should be provided in a format understood by the tool, such as seconds (s), minutes (m), or hours (h). For example, '30s' for 30 seconds, '5m' for 5 minutes, or '1h' for one hour.").Default("30s").Duration()
 	skipUpdate       = e2e.Flag("skip-update", "Skip the update step of the test.").Default("false").Bool()
 	skipImport       = e2e.Flag("skip-import", "Skip the import step of the test.").Default("false").Bool()
 	skipWebhookCheck = e2e.Flag("skip-webhook-check", "Skip the webhook endpoint health check.").Default("false").Bool()
 	useLibraryMode   = e2e.Flag("use-library-mode", "Use library mode instead of CLI fork mode. When enabled, chainsaw and crossplane are used as Go libraries instead of external CLI commands.").Default("false").Bool()
+	orderedApply     = e2e.Flag("ordered-apply", "Apply the resources tier by tier in dependency order. The resources of a tier are asserted before the next tier is applied.\n"+
+		"Dependencies are resolved from the reference fields and the \"uptest.upbound.io/depends-on\" annotation.").Default("false").Bool()
+	importMode = e2e.Flag("import-mode", "The way the resources are imported in the import step. \"restart\" pauses the resources, restarts the provider controllers and clears the resource statuses.\n"+
+		"\"fresh\" deletes the resources while orphaning the external resources, imports them into new objects with only the external name and the Observe management policy, and then re-adopts them.").Default(string(config.ImportModeRestart)).Enum(string(config.ImportModeRestart), string(config.ImportModeFresh))
+	observeOnlyTest = e2e.Flag("observe-only-test", "Test the observe-only management policy after the apply step. An observe-only twin of each managed resource is created with the same external name,\n"+
+		"asserted and deleted, and the managed resource is asserted to be still ready.").Default("false").Bool()
+	stabilityWindow = e2e.Flag("stability-window", "Duration to watch the managed resources for changes after they become ready. The test fails if the generation, spec.forProvider or\n"+
+		"the external resource of a managed resource keeps changing during the window, i.e. it changes more than once. Zero disables the stability check.").Default("0s").Duration()
+	skipLeakCheck = e2e.Flag("skip-leak-check", "Skip checking whether the external resources of the deleted managed resources still exist after the delete step.").Default("false").Bool()
+	artifactsDir  = e2e.Flag("artifacts-dir", "Directory the diagnostics bundle of a failed phase is written to. The test directory is used if not set.").Default("").String()
+	resume        = e2e.Flag("resume", "Resume the failed run whose test directory is kept. The phases that passed are skipped if the tested resources still exist, and the run starts at the failed phase. "+
+		"It implies --no-cleanup-on-failure, so that the resources of a failed run are kept.").Default("false").Bool()
+	noCleanupOnFailure = e2e.Flag("no-cleanup-on-failure", "Do not execute the delete step when a phase fails or the run is interrupted, so that the resources can be inspected. "+
+		"They can be deleted later with the cleanup command.").Default("false").Bool()
+	keepArtifacts = e2e.Flag("keep-artifacts", "Keep the test directory at the end of the run even if the test succeeds. It's always kept if the test fails.").Default("false").Bool()
+	progress      = e2e.Flag("progress", "Show a live progress table of the tested resources instead of the logs when stdout is an interactive terminal. "+
+		"The logs are written to a file in the meantime. Not supported in library mode.").Default("true").Bool()
+)
+
+var (
+	runFromDir        = runCmd.Flag("from-dir", "Test directory of a previous e2e run the test case is rendered in.").Required().ExistingDir()
+	runPhase          = runCmd.Flag("phase", "Only run the phase, one of apply, observe, drift, update, import or delete. All rendered phases are run if not set.").Default("").String()
+	runDefaultTimeout = runCmd.Flag("default-timeout", "Default timeout in seconds for the test. Timeout could be overridden per resource using \"uptest.upbound.io/timeout\" annotation.").Default("1200s").Duration()
+	runCleanupTimeout = runCmd.Flag("cleanup-timeout", "Timeout of waiting for the managed resources to be deleted at the end of the delete step.").Default("600s").Duration()
+	runImportMode     = runCmd.Flag("import-mode", "The way the resources are imported in the import step. It must match the mode the test case is rendered with.").Default(string(config.ImportModeRestart)).Enum(string(config.ImportModeRestart), string(config.ImportModeFresh))
+	runSkipLeakCheck  = runCmd.Flag("skip-leak-check", "Skip checking whether the external resources of the deleted managed resources still exist after the delete step.").Default("false").Bool()
+	runLibraryMode    = runCmd.Flag("use-library-mode", "Use library mode instead of CLI fork mode.").Default("false").Bool()
+	runArtifactsDir   = runCmd.Flag("artifacts-dir", "Directory the diagnostics bundle of a failed phase is written to. The test directory is used if not set.").Default("").String()
+	runNoCleanup      = runCmd.Flag("no-cleanup-on-failure", "Do not execute the delete step when a phase fails or the run is interrupted.").Default("false").Bool()
+	runLogInterval    = runCmd.Flag("log-collect-interval", "Interval of logging the summary of the tested resources, if they changed.").Default("30s").Duration()
 )
 
 func main() {
-	if kingpin.MustParse(app.Parse(os.Args[1:])) == e2e.FullCommand() {
-		e2eTests()
+	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
+	kingpin.FatalIfError(logging.Setup(*logLevel, logging.Format(*logFormat)), "cannot set up the logger")
+	ctx := interruptContext()
+	switch cmd {
+	case e2e.FullCommand():
+		e2eTests(ctx)
+	case cleanupCmd.FullCommand():
+		fatalIfError(ctx, pkg.Cleanup(ctx, *cleanupRunID, *cleanupWaitTimeout), "cannot clean up run %s", *cleanupRunID)
+	case gcCmd.FullCommand():
+		opts := cluster.GCOptions{MinAge: *gcOlderThan, RunID: *gcRunID, Groups: *gcGroups}
+		fatalIfError(ctx, pkg.GC(ctx, opts, *gcDryRun, *gcTimeout), "cannot garbage collect the managed resources")
+	case runCmd.FullCommand():
+		runRendered(ctx)
 	}
 }
 
-func e2eTests() {
+// interruptContext returns a context that is canceled on the first SIGINT or
+// SIGTERM, which stops the current phase and cleans the resources up. The
+// process exits immediately on the second one. The signals are received on a
+// channel registered before any of them arrives, with room for both, so that
+// the second one is not lost while the context is being canceled.
+func interruptContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		cancel()
+		slog.Warn("Interrupted, stopping the current phase and cleaning up. Interrupt again to exit immediately.")
+		<-sigs
+		slog.Error("Interrupted again, exiting without cleaning up")
+		os.Exit(exitCodeInterrupted)
+	}()
+	return ctx
+}
+
+// fatalIfError exits with the exit code of an interrupted run if the context
+// is canceled by a signal, or with the exit code of failed tests otherwise,
+// if err is not nil.
+func fatalIfError(ctx context.Context, err error, format string, args ...interface{}) {
+	if err != nil && ctx.Err() != nil {
+		app.Errorf("interrupted: "+format+": %s", append(args, err)...)
+		os.Exit(exitCodeInterrupted)
+	}
+	kingpin.FatalIfError(err, format, args...)
+}
+
+func runRendered(ctx context.Context) {
+	dir, err := filepath.Abs(*runFromDir)
+	kingpin.FatalIfError(err, "cannot get absolute path of test directory")
+	automatedTest := pkg.NewAutomatedTestBuilder().
+		SetDirectory(dir).
+		SetDefaultTimeout(*runDefaultTimeout).
+		SetCleanupTimeout(*runCleanupTimeout).
+		SetImportMode(config.ImportMode(*runImportMode)).
+		SetSkipLeakCheck(*runSkipLeakCheck).
+		SetArtifactsDir(*runArtifactsDir).
+		SetUseLibraryMode(*runLibraryMode).
+		SetLogCollectionInterval(*runLogInterval).
+		SetNoCleanupOnFailure(*runNoCleanup).
+		Build()
+	fatalIfError(ctx, pkg.RunRenderedContext(ctx, automatedTest, *runPhase), "cannot run the rendered tests successfully")
+}
+
+func e2eTests(ctx context.Context) {
 	cd, err := os.Getwd()
 	if err != nil {
 		kingpin.FatalIfError(err, "cannot get current directory")
@@ -99,17 +234,32 @@ func e2eTests() {
 		SetTeardownScriptPath(teardownPath).
 		SetDefaultConditions(strings.Split(*defaultConditions, ",")).
 		SetDefaultTimeout(*defaultTimeout).
+		SetApplyTimeout(*applyTimeout).
+		SetUpdateTimeout(*updateTimeout).
+		SetImportTimeout(*importTimeout).
+		SetDeleteTimeout(*deleteTimeout).
 		SetDirectory(*testDir).
 		SetSkipDelete(*skipDelete).
 		SetSkipUpdate(*skipUpdate).
 		SetSkipImport(*skipImport).
 		SetSkipWebhookCheck(*skipWebhookCheck).
+		SetImportMode(config.ImportMode(*importMode)).
+		SetObserveOnlyTest(*observeOnlyTest).
+		SetStabilityWindow(*stabilityWindow).
+		SetSkipLeakCheck(*skipLeakCheck).
+		SetArtifactsDir(*artifactsDir).
+		SetKeepArtifacts(*keepArtifacts).
+		SetNoCleanupOnFailure(*noCleanupOnFailure).
+		SetResume(*resume).
+		SetProgress(*progress).
 		SetOnlyCleanUptestResources(*onlyCleanUptestResources).
+		SetWaitAllManaged(*waitAllManaged).
+		SetCleanupTimeout(*cleanupTimeout).
 		SetRenderOnly(*renderOnly).
 		SetLogCollectionInterval(*logCollectInterval).
 		SetUseLibraryMode(*useLibraryMode).
+		SetOrderedApply(*orderedApply).
 		Build()
 
-	ctx := context.Background()
-	kingpin.FatalIfError(pkg.RunTestContext(ctx, automatedTest), "cannot run e2e tests successfully")
+	fatalIfError(ctx, pkg.RunTestContext(ctx, automatedTest), "cannot run e2e tests successfully")
 }
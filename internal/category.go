@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+// kubernetesGroups are the API groups of the objects that are not tested
+// but only applied and deleted, in addition to the core group and the
+// *.k8s.io groups.
+var kubernetesGroups = map[string]bool{
+	"apps":                        true,
+	"batch":                       true,
+	"policy":                      true,
+	"autoscaling":                 true,
+	"pkg.crossplane.io":           true,
+	"apiextensions.crossplane.io": true,
+	"ops.crossplane.io":           true,
+	"protection.crossplane.io":    true,
+}
+
+// classify determines the category of the object. Objects with a
+// spec.forProvider are managed resources. Cluster scoped objects without a
+// spec.forProvider are composite resources and namespaced ones are claims,
+// unless they have a spec.crossplane, which only the composite resources of
+// Crossplane v2 have. The category can be overridden with the
+// uptest.upbound.io/category annotation.
+func classify(o *unstructured.Unstructured) (config.Category, error) {
+	if v, ok := o.GetAnnotations()[config.AnnotationKeyCategory]; ok {
+		switch c := config.Category(v); c {
+		case config.CategoryManaged, config.CategoryComposite, config.CategoryClaim, config.CategoryProviderConfig, config.CategoryKubernetes:
+			return c, nil
+		default:
+			return "", errors.Errorf("unknown category %q in annotation %s", v, config.AnnotationKeyCategory)
+		}
+	}
+
+	if _, ok, _ := unstructured.NestedMap(o.Object, "spec", "forProvider"); ok {
+		return config.CategoryManaged, nil
+	}
+	gvk := o.GroupVersionKind()
+	if gvk.Group == "" || strings.HasSuffix(gvk.Group, ".k8s.io") || kubernetesGroups[gvk.Group] {
+		return config.CategoryKubernetes, nil
+	}
+	if strings.HasSuffix(gvk.Kind, "ProviderConfig") || strings.HasSuffix(gvk.Kind, "ProviderConfigUsage") {
+		return config.CategoryProviderConfig, nil
+	}
+	if _, ok, _ := unstructured.NestedMap(o.Object, "spec", "crossplane"); ok || o.GetNamespace() == "" {
+		return config.CategoryComposite, nil
+	}
+	return config.CategoryClaim, nil
+}
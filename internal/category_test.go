@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestClassify(t *testing.T) {
+	type want struct {
+		category config.Category
+		err      bool
+	}
+	tests := map[string]struct {
+		manifest string
+		want     want
+	}{
+		"ManagedResource": {
+			manifest: `apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+`,
+			want: want{category: config.CategoryManaged},
+		},
+		"Secret": {
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: example
+  namespace: upbound-system
+`,
+			want: want{category: config.CategoryKubernetes},
+		},
+		"Composition": {
+			manifest: `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: example
+spec:
+  mode: Pipeline
+`,
+			want: want{category: config.CategoryKubernetes},
+		},
+		"ProviderConfig": {
+			manifest: `apiVersion: aws.upbound.io/v1beta1
+kind: ProviderConfig
+metadata:
+  name: default
+spec:
+  credentials:
+    source: Secret
+`,
+			want: want{category: config.CategoryProviderConfig},
+		},
+		"Composite": {
+			manifest: `apiVersion: platform.example.org/v1alpha1
+kind: XNetwork
+metadata:
+  name: example
+spec:
+  parameters:
+    region: us-west-1
+`,
+			want: want{category: config.CategoryComposite},
+		},
+		"NamespacedCompositeV2": {
+			manifest: `apiVersion: platform.example.org/v1alpha1
+kind: Network
+metadata:
+  name: example
+  namespace: default
+spec:
+  crossplane:
+    compositionRef:
+      name: example
+`,
+			want: want{category: config.CategoryComposite},
+		},
+		"Claim": {
+			manifest: `apiVersion: platform.example.org/v1alpha1
+kind: Network
+metadata:
+  name: example
+  namespace: default
+spec:
+  parameters:
+    region: us-west-1
+`,
+			want: want{category: config.CategoryClaim},
+		},
+		"Annotation": {
+			manifest: `apiVersion: platform.example.org/v1alpha1
+kind: Network
+metadata:
+  name: example
+  namespace: default
+  annotations:
+    uptest.upbound.io/category: Composite
+`,
+			want: want{category: config.CategoryComposite},
+		},
+		"InvalidAnnotation": {
+			manifest: `apiVersion: platform.example.org/v1alpha1
+kind: Network
+metadata:
+  name: example
+  annotations:
+    uptest.upbound.io/category: Unknown
+`,
+			want: want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(tc.manifest), &u.Object); err != nil {
+				t.Fatalf("cannot unmarshal manifest: %v", err)
+			}
+			got, err := classify(u)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("classify(...): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.category, got); diff != "" {
+				t.Errorf("classify(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
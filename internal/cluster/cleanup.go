@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+// cleanupStages are the kinds whose objects are cleaned up, in the order
+// they are deleted. The objects of a stage are deleted only after the
+// objects of the previous stages are gone, so that, for example, the
+// ProviderConfigs and the Secrets the managed resources depend on are
+// deleted after the managed resources.
+var cleanupStages = []struct {
+	// categories are the categories of the custom resources of the stage.
+	categories []string
+	// kindSuffixes are the suffixes of the kinds of the custom resources of
+	// the stage.
+	kindSuffixes []string
+	// others selects the custom resources that are not in any other stage.
+	others bool
+	// kinds are the built-in kinds of the stage.
+	kinds []schema.GroupVersionKind
+}{
+	{categories: []string{"claim", "composite"}},
+	{categories: []string{"managed"}},
+	{kindSuffixes: []string{"ProviderConfig", "ProviderConfigUsage"}},
+	{others: true},
+	{kinds: []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Secret"},
+		{Version: "v1", Kind: "ConfigMap"},
+	}},
+	{kinds: []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Namespace"},
+	}},
+}
+
+// RunObjects returns the objects labelled with the specified run ID, grouped
+// by the stages they are deleted in. The custom resources in the claim,
+// composite and managed categories, the ProviderConfigs, the other custom
+// resources, and the Secrets, ConfigMaps and Namespaces are looked up.
+func (c *Client) RunObjects(ctx context.Context, runID string) ([][]*unstructured.Unstructured, error) {
+	crds := &extv1.CustomResourceDefinitionList{}
+	if err := c.kube.List(ctx, crds); err != nil {
+		return nil, errors.Wrap(err, "cannot list CustomResourceDefinitions")
+	}
+	sel := labels.SelectorFromSet(labels.Set{config.LabelKeyRunID: runID})
+	res := make([][]*unstructured.Unstructured, 0, len(cleanupStages))
+	for _, stage := range cleanupStages {
+		gvks := slices.Clone(stage.kinds)
+		for _, crd := range crds.Items {
+			if stage.others {
+				if staged(crd) {
+					continue
+				}
+			} else if !hasCategory(crd, stage.categories) && !hasKindSuffix(crd, stage.kindSuffixes) {
+				continue
+			}
+			if gvk, ok := storageVersionKind(crd); ok {
+				gvks = append(gvks, gvk)
+			}
+		}
+		var objs []*unstructured.Unstructured
+		for _, gvk := range gvks {
+			l := &unstructured.UnstructuredList{}
+			l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := c.kube.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+				return nil, errors.Wrapf(err, "cannot list %s", gvk.GroupKind())
+			}
+			for i := range l.Items {
+				objs = append(objs, &l.Items[i])
+			}
+		}
+		res = append(res, objs)
+	}
+	return res, nil
+}
+
+// DeleteStages deletes the objects of each stage and waits until they are
+// gone before deleting the objects of the next stage.
+func (c *Client) DeleteStages(ctx context.Context, stages [][]*unstructured.Unstructured, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, objs := range stages {
+		for _, o := range objs {
+			if err := c.kube.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "cannot delete %s %s", o.GroupVersionKind().GroupKind(), objectKey(o))
+			}
+			slog.InfoContext(ctx, "Deleted object", logging.Resource(strings.ToLower(o.GroupVersionKind().GroupKind().String()), o.GetNamespace(), o.GetName()))
+		}
+		if err := c.waitForDeletion(ctx, objs, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) waitForDeletion(ctx context.Context, objs []*unstructured.Unstructured, timeout time.Duration) error {
+	remaining := objs
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		var left []*unstructured.Unstructured
+		for _, o := range remaining {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(o.GroupVersionKind())
+			err := c.kube.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, u)
+			if resource.IgnoreNotFound(err) != nil {
+				return false, errors.Wrapf(err, "cannot get %s %s", o.GroupVersionKind().GroupKind(), objectKey(o))
+			}
+			if err == nil {
+				left = append(left, o)
+			}
+		}
+		remaining = left
+		return len(remaining) == 0, nil
+	})
+	if err != nil && len(remaining) > 0 {
+		return errors.Wrapf(err, "cannot wait for the deletion of %d objects, e.g. %s %s", len(remaining), remaining[0].GroupVersionKind().GroupKind(), objectKey(remaining[0]))
+	}
+	return errors.Wrap(err, "cannot wait for the deletion of the objects")
+}
+
+// hasCategory reports whether the CustomResourceDefinition is in any of the
+// specified categories.
+func hasCategory(crd extv1.CustomResourceDefinition, categories []string) bool {
+	for _, c := range crd.Spec.Names.Categories {
+		if slices.Contains(categories, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKindSuffix reports whether the kind of the CustomResourceDefinition
+// ends with any of the specified suffixes.
+func hasKindSuffix(crd extv1.CustomResourceDefinition, suffixes []string) bool {
+	for _, s := range suffixes {
+		if strings.HasSuffix(crd.Spec.Names.Kind, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// staged reports whether the custom resources of the
+// CustomResourceDefinition are in a cleanup stage other than the one for
+// the remaining custom resources.
+func staged(crd extv1.CustomResourceDefinition) bool {
+	for _, stage := range cleanupStages {
+		if hasCategory(crd, stage.categories) || hasKindSuffix(crd, stage.kindSuffixes) {
+			return true
+		}
+	}
+	return false
+}
+
+// storageVersionKind returns the kind of the CustomResourceDefinition at its
+// storage version.
+func storageVersionKind(crd extv1.CustomResourceDefinition) (schema.GroupVersionKind, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}, true
+		}
+	}
+	return schema.GroupVersionKind{}, false
+}
+
+func objectKey(o client.Object) string {
+	if o.GetNamespace() == "" {
+		return o.GetName()
+	}
+	return o.GetNamespace() + "/" + o.GetName()
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func categorized(group, kind string, categories ...string) *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: kind + "." + group},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group:    group,
+			Names:    extv1.CustomResourceDefinitionNames{Kind: kind, Categories: categories},
+			Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1alpha1"}, {Name: "v1beta1", Storage: true}},
+		},
+	}
+}
+
+func object(apiVersion, kind, namespace, name, runID string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if runID != "" {
+		u.SetLabels(map[string]string{"uptest.upbound.io/run-id": runID})
+	}
+	return u
+}
+
+func TestRunObjects(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := extv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		categorized("s3.aws.upbound.io", "Bucket", "crossplane", "managed", "aws"),
+		categorized("example.org", "XNetwork", "crossplane", "composite"),
+		categorized("example.org", "Network", "crossplane", "claim"),
+		categorized("pkg.crossplane.io", "Provider", "crossplane", "pkg"),
+		categorized("aws.upbound.io", "ProviderConfig"),
+		categorized("aws.upbound.io", "ClusterProviderConfig"),
+		object("s3.aws.upbound.io/v1beta1", "Bucket", "", "bucket", "abcde12345"),
+		object("s3.aws.upbound.io/v1beta1", "Bucket", "", "other-bucket", "fghij67890"),
+		object("example.org/v1beta1", "Network", "default", "network", "abcde12345"),
+		object("pkg.crossplane.io/v1beta1", "Provider", "", "provider", "abcde12345"),
+		object("aws.upbound.io/v1beta1", "ProviderConfig", "", "default", "abcde12345"),
+		object("aws.upbound.io/v1beta1", "ClusterProviderConfig", "", "default", "abcde12345"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default", Labels: map[string]string{"uptest.upbound.io/run-id": "abcde12345"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-creds", Namespace: "default"}},
+	).Build()
+
+	got, err := NewWithClient(kube).RunObjects(context.Background(), "abcde12345")
+	if err != nil {
+		t.Fatalf("RunObjects(...): %v", err)
+	}
+	names := make([][]string, len(got))
+	for i, objs := range got {
+		for _, o := range objs {
+			names[i] = append(names[i], o.GetKind()+" "+objectKey(o))
+		}
+	}
+	want := [][]string{
+		{"Network default/network"},
+		{"Bucket bucket"},
+		{"ClusterProviderConfig default", "ProviderConfig default"},
+		{"Provider provider"},
+		{"Secret default/creds"},
+		nil,
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("RunObjects(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDeleteStages(t *testing.T) {
+	objs := []client.Object{
+		object("s3.aws.upbound.io/v1beta1", "Bucket", "", "bucket", "abcde12345"),
+		object("v1", "Secret", "default", "creds", "abcde12345"),
+	}
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	stages := [][]*unstructured.Unstructured{
+		{objs[0].(*unstructured.Unstructured)},
+		{objs[1].(*unstructured.Unstructured)},
+	}
+	if err := NewWithClient(kube).DeleteStages(context.Background(), stages, time.Minute); err != nil {
+		t.Fatalf("DeleteStages(...): %v", err)
+	}
+	for _, o := range objs {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+		if err := kube.Get(context.Background(), client.ObjectKeyFromObject(o), u); err == nil {
+			t.Errorf("DeleteStages(...): %s was not deleted", objectKey(o))
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+// Package cluster contains the operations uptest performs directly on the
+// control plane the tests are run against, instead of through the rendered
+// chainsaw test files.
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	pkgv1 "github.com/crossplane/crossplane/v2/apis/pkg/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+const (
+	// annotationKeyRestartedAt is the pod template annotation that is set to
+	// restart the pods of a Deployment, like kubectl rollout restart does.
+	annotationKeyRestartedAt = "kubectl.kubernetes.io/restartedAt"
+
+	pollInterval = 5 * time.Second
+)
+
+// Client performs operations on the control plane.
+type Client struct {
+	kube client.Client
+	// pods is used to get the pod logs, which the Kubernetes client does not
+	// support.
+	pods corev1client.PodsGetter
+	// dynamic is used by the informers watching the resources.
+	dynamic dynamic.Interface
+}
+
+// New returns a Client for the control plane of the current kubeconfig
+// context.
+func New() (*Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the kubeconfig")
+	}
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, "cannot add the Kubernetes types to the scheme")
+	}
+	if err := extv1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, "cannot add the CustomResourceDefinition types to the scheme")
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the Kubernetes client")
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the Kubernetes clientset")
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the dynamic client")
+	}
+	c := NewWithClient(kube)
+	c.pods = cs.CoreV1()
+	c.dynamic = dyn
+	return c, nil
+}
+
+// NewWithClient returns a Client that uses the specified Kubernetes client.
+func NewWithClient(kube client.Client) *Client {
+	return &Client{kube: kube}
+}
+
+// Pause pauses the reconciliation of the specified resource.
+func (c *Client) Pause(ctx context.Context, r config.Resource) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.FromAPIVersionAndKind(r.APIVersion, r.Kind))
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, u); err != nil {
+		return errors.Wrapf(err, "cannot get %s/%s", r.KindGroup, r.Name)
+	}
+	p := client.MergeFrom(u.DeepCopy())
+	meta.AddAnnotations(u, map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+	return errors.Wrapf(c.kube.Patch(ctx, u, p), "cannot pause %s/%s", r.KindGroup, r.Name)
+}
+
+// PrepareDeletion prepares the specified managed resource, if it still
+// exists, for the deletion of its external resource: its reconciliation is
+// resumed and its management policies are reset to full control, e.g. after
+// a failed fresh import left an Observe-only resource in its place. The
+// deletion policy of a cluster scoped resource is reset to Delete, as it's
+// not a field of the namespaced ones.
+func (c *Client) PrepareDeletion(ctx context.Context, r config.Resource) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.FromAPIVersionAndKind(r.APIVersion, r.Kind))
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, u); err != nil {
+		return errors.Wrapf(client.IgnoreNotFound(err), "cannot get %s/%s", r.KindGroup, r.Name)
+	}
+	p := client.MergeFrom(u.DeepCopy())
+	meta.RemoveAnnotations(u, meta.AnnotationKeyReconciliationPaused)
+	if err := unstructured.SetNestedStringSlice(u.Object, []string{"*"}, "spec", "managementPolicies"); err != nil {
+		return errors.Wrapf(err, "cannot set the management policies of %s/%s", r.KindGroup, r.Name)
+	}
+	if r.Namespace == "" {
+		if err := unstructured.SetNestedField(u.Object, "Delete", "spec", "deletionPolicy"); err != nil {
+			return errors.Wrapf(err, "cannot set the deletion policy of %s/%s", r.KindGroup, r.Name)
+		}
+	}
+	return errors.Wrapf(c.kube.Patch(ctx, u, p), "cannot prepare %s/%s for deletion", r.KindGroup, r.Name)
+}
+
+// Exists returns whether the specified resource exists.
+func (c *Client) Exists(ctx context.Context, r config.Resource) (bool, error) {
+	_, err := c.get(ctx, r)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ProviderDeployments returns the Deployments of the providers that
+// reconcile the specified kinds. The providers are found through the
+// ProviderRevisions owning the CustomResourceDefinitions of the kinds, so
+// the Deployments of the other providers are not returned.
+func (c *Client) ProviderDeployments(ctx context.Context, kinds []schema.GroupKind) ([]appsv1.Deployment, error) {
+	crds := &extv1.CustomResourceDefinitionList{}
+	if err := c.kube.List(ctx, crds); err != nil {
+		return nil, errors.Wrap(err, "cannot list CustomResourceDefinitions")
+	}
+	revisions := map[types.UID]string{}
+	for _, gk := range kinds {
+		crd := findCRD(crds.Items, gk)
+		if crd == nil {
+			return nil, errors.Errorf("cannot find the CustomResourceDefinition of %s", gk)
+		}
+		for _, ref := range revisionRefs(crd) {
+			revisions[ref.UID] = ref.Name
+		}
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.kube.List(ctx, deployments); err != nil {
+		return nil, errors.Wrap(err, "cannot list Deployments")
+	}
+	var res []appsv1.Deployment
+	for _, d := range deployments.Items {
+		if ownedByRevision(d, revisions) {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+// RestartDeployments restarts the pods of the specified Deployments and
+// waits until their rollouts are complete.
+func (c *Client) RestartDeployments(ctx context.Context, deployments []appsv1.Deployment, timeout time.Duration) error {
+	now := time.Now().Format(time.RFC3339)
+	for i := range deployments {
+		d := &deployments[i]
+		p := client.MergeFrom(d.DeepCopy())
+		if d.Spec.Template.Annotations == nil {
+			d.Spec.Template.Annotations = map[string]string{}
+		}
+		d.Spec.Template.Annotations[annotationKeyRestartedAt] = now
+		if err := c.kube.Patch(ctx, d, p); err != nil {
+			return errors.Wrapf(err, "cannot restart Deployment %s/%s", d.Namespace, d.Name)
+		}
+		slog.InfoContext(ctx, "Restarted provider Deployment", logging.Resource("deployment.apps", d.Namespace, d.Name))
+	}
+	for _, d := range deployments {
+		if err := c.waitForRollout(ctx, types.NamespacedName{Namespace: d.Namespace, Name: d.Name}, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) waitForRollout(ctx context.Context, nn types.NamespacedName, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		d := &appsv1.Deployment{}
+		if err := c.kube.Get(ctx, nn, d); err != nil {
+			return false, errors.Wrapf(err, "cannot get Deployment %s", nn)
+		}
+		return rolloutComplete(d), nil
+	})
+	return errors.Wrapf(err, "cannot wait for the rollout of Deployment %s", nn)
+}
+
+// findCRD returns the CustomResourceDefinition of the specified kind. The
+// kind is matched case insensitively, as the kinds of the tested resources
+// are lower case.
+func findCRD(crds []extv1.CustomResourceDefinition, gk schema.GroupKind) *extv1.CustomResourceDefinition {
+	for i := range crds {
+		if crds[i].Spec.Group == gk.Group && strings.EqualFold(crds[i].Spec.Names.Kind, gk.Kind) {
+			return &crds[i]
+		}
+	}
+	return nil
+}
+
+// revisionRefs returns the references to the ProviderRevisions owning the
+// CustomResourceDefinition. All revisions of a provider own its CRDs, but
+// only the active one is the controller, so the controller is preferred.
+func revisionRefs(crd *extv1.CustomResourceDefinition) []metav1.OwnerReference {
+	var all []metav1.OwnerReference
+	for _, ref := range crd.GetOwnerReferences() {
+		if ref.Kind != pkgv1.ProviderRevisionKind {
+			continue
+		}
+		if ref.Controller != nil && *ref.Controller {
+			return []metav1.OwnerReference{ref}
+		}
+		all = append(all, ref)
+	}
+	return all
+}
+
+// ownedByRevision reports whether the Deployment belongs to one of the
+// specified ProviderRevisions, either by an owner reference or by the
+// revision label.
+func ownedByRevision(d appsv1.Deployment, revisions map[types.UID]string) bool {
+	for _, ref := range d.GetOwnerReferences() {
+		if _, ok := revisions[ref.UID]; ok {
+			return true
+		}
+	}
+	rev, ok := d.GetLabels()[pkgv1.LabelRevision]
+	if !ok {
+		return false
+	}
+	for _, name := range revisions {
+		if name == rev {
+			return true
+		}
+	}
+	return false
+}
+
+// rolloutComplete reports whether the rollout of the Deployment is complete,
+// using the same conditions as kubectl rollout status.
+func rolloutComplete(d *appsv1.Deployment) bool {
+	if d.Status.ObservedGeneration < d.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.UpdatedReplicas >= replicas &&
+		d.Status.Replicas <= d.Status.UpdatedReplicas &&
+		d.Status.AvailableReplicas >= d.Status.UpdatedReplicas
+}
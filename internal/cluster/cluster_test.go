@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func crd(group, kind, revision string, controller bool) *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kind + "." + group,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "pkg.crossplane.io/v1",
+				Kind:       "ProviderRevision",
+				Name:       revision,
+				UID:        types.UID("uid-" + revision),
+				Controller: ptr.To(controller),
+			}},
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: extv1.CustomResourceDefinitionNames{Kind: kind},
+		},
+	}
+}
+
+func deployment(name, revision string, owned bool) *appsv1.Deployment {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "crossplane-system",
+		},
+	}
+	if owned {
+		d.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "pkg.crossplane.io/v1",
+			Kind:       "ProviderRevision",
+			Name:       revision,
+			UID:        types.UID("uid-" + revision),
+		}}
+	} else {
+		d.Labels = map[string]string{"pkg.crossplane.io/revision": revision}
+	}
+	return d
+}
+
+func TestProviderDeployments(t *testing.T) {
+	type args struct {
+		objs  []client.Object
+		kinds []schema.GroupKind
+	}
+	type want struct {
+		names []string
+		err   bool
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"OwnerReference": {
+			args: args{
+				objs: []client.Object{
+					crd("s3.aws.upbound.io", "Bucket", "provider-aws-s3-abc", true),
+					crd("ec2.aws.upbound.io", "VPC", "provider-aws-ec2-def", true),
+					deployment("provider-aws-s3-abc", "provider-aws-s3-abc", true),
+					deployment("provider-aws-ec2-def", "provider-aws-ec2-def", true),
+				},
+				kinds: []schema.GroupKind{{Group: "s3.aws.upbound.io", Kind: "bucket"}},
+			},
+			want: want{names: []string{"provider-aws-s3-abc"}},
+		},
+		"RevisionLabel": {
+			args: args{
+				objs: []client.Object{
+					crd("s3.aws.upbound.io", "Bucket", "provider-aws-s3-abc", true),
+					deployment("custom-runtime", "provider-aws-s3-abc", false),
+					deployment("other", "provider-aws-ec2-def", false),
+				},
+				kinds: []schema.GroupKind{{Group: "s3.aws.upbound.io", Kind: "Bucket"}},
+			},
+			want: want{names: []string{"custom-runtime"}},
+		},
+		"MissingCRD": {
+			args: args{
+				kinds: []schema.GroupKind{{Group: "s3.aws.upbound.io", Kind: "Bucket"}},
+			},
+			want: want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := extv1.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			c := NewWithClient(fake.NewClientBuilder().WithScheme(s).WithObjects(tc.args.objs...).Build())
+			got, err := c.ProviderDeployments(context.Background(), tc.args.kinds)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("ProviderDeployments(...): want error %t, got %v", tc.want.err, err)
+			}
+			var names []string
+			for _, d := range got {
+				names = append(names, d.Name)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("ProviderDeployments(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRolloutComplete(t *testing.T) {
+	tests := map[string]struct {
+		d    *appsv1.Deployment
+		want bool
+	}{
+		"Complete": {
+			d: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+			want: true,
+		},
+		"NotObserved": {
+			d: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+		},
+		"OldReplicaRunning": {
+			d: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+		},
+		"NotAvailable": {
+			d: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := rolloutComplete(tc.d); got != tc.want {
+				t.Errorf("rolloutComplete(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	tests := map[string]struct {
+		r    config.Resource
+		want bool
+	}{
+		"Exists": {
+			r:    config.Resource{APIVersion: "v1", Kind: "ConfigMap", KindGroup: "configmap", Namespace: "default", Name: "example"},
+			want: true,
+		},
+		"NotFound": {
+			r: config.Resource{APIVersion: "v1", Kind: "ConfigMap", KindGroup: "configmap", Namespace: "default", Name: "other"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewWithClient(fake.NewClientBuilder().WithObjects(cm).Build())
+			got, err := c.Exists(context.Background(), tc.r)
+			if err != nil {
+				t.Fatalf("Exists(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Exists(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrepareDeletion(t *testing.T) {
+	bucket := func(namespace string, annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+		u := object("s3.aws.upbound.io/v1beta1", "Bucket", namespace, "example", "")
+		u.SetAnnotations(annotations)
+		u.Object["spec"] = spec
+		return u
+	}
+	tests := map[string]struct {
+		obj  *unstructured.Unstructured
+		want *unstructured.Unstructured
+	}{
+		"PausedObserveOnly": {
+			obj: bucket("", map[string]string{"crossplane.io/paused": "true", "other": "value"}, map[string]interface{}{
+				"managementPolicies": []interface{}{"Observe"},
+				"deletionPolicy":     "Orphan",
+			}),
+			want: bucket("", map[string]string{"other": "value"}, map[string]interface{}{
+				"managementPolicies": []interface{}{"*"},
+				"deletionPolicy":     "Delete",
+			}),
+		},
+		"Namespaced": {
+			obj: bucket("default", nil, map[string]interface{}{
+				"managementPolicies": []interface{}{"Observe", "Create", "Update", "LateInitialize"},
+			}),
+			want: bucket("default", nil, map[string]interface{}{
+				"managementPolicies": []interface{}{"*"},
+			}),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kube := fake.NewClientBuilder().WithObjects(tc.obj).Build()
+			c := NewWithClient(kube)
+			r := config.Resource{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", KindGroup: "bucket.s3.aws.upbound.io", Namespace: tc.obj.GetNamespace(), Name: "example"}
+			if err := c.PrepareDeletion(context.Background(), r); err != nil {
+				t.Fatalf("PrepareDeletion(...): %v", err)
+			}
+			got := &unstructured.Unstructured{}
+			got.SetGroupVersionKind(tc.obj.GroupVersionKind())
+			if err := kube.Get(context.Background(), types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, got); err != nil {
+				t.Fatalf("cannot get the Bucket: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.GetAnnotations(), got.GetAnnotations()); diff != "" {
+				t.Errorf("PrepareDeletion(...): -want annotations, +got annotations:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.Object["spec"], got.Object["spec"]); diff != "" {
+				t.Errorf("PrepareDeletion(...): -want spec, +got spec:\n%s", diff)
+			}
+		})
+	}
+	t.Run("NotFound", func(t *testing.T) {
+		c := NewWithClient(fake.NewClientBuilder().Build())
+		if err := c.PrepareDeletion(context.Background(), config.Resource{APIVersion: "v1", Kind: "ConfigMap", KindGroup: "configmap", Namespace: "default", Name: "example"}); err != nil {
+			t.Errorf("PrepareDeletion(...): %v", err)
+		}
+	})
+}
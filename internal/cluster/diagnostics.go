@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	pkgv1 "github.com/crossplane/crossplane/v2/apis/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+// Resource returns the YAML of the specified resource.
+func (c *Client) Resource(ctx context.Context, r config.Resource) ([]byte, error) {
+	u, err := c.get(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.Marshal(u.Object)
+	return b, errors.Wrapf(err, "cannot marshal %s/%s", r.KindGroup, r.Name)
+}
+
+// Events returns the YAML of the events of the specified resource.
+func (c *Client) Events(ctx context.Context, r config.Resource) ([]byte, error) {
+	l := &corev1.EventList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, "cannot list events")
+	}
+	res := &corev1.EventList{}
+	for _, e := range l.Items {
+		o := e.InvolvedObject
+		if o.APIVersion == r.APIVersion && o.Kind == r.Kind && o.Name == r.Name && o.Namespace == r.Namespace {
+			res.Items = append(res.Items, e)
+		}
+	}
+	b, err := yaml.Marshal(res)
+	return b, errors.Wrapf(err, "cannot marshal the events of %s/%s", r.KindGroup, r.Name)
+}
+
+// Providers returns the YAML of the Providers and ProviderRevisions in the
+// control plane.
+func (c *Client) Providers(ctx context.Context) ([]byte, error) {
+	var res []interface{}
+	for _, kind := range []string{pkgv1.ProviderKind, pkgv1.ProviderRevisionKind} {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(pkgv1.SchemeGroupVersion.WithKind(kind + "List"))
+		if err := c.kube.List(ctx, l); err != nil {
+			return nil, errors.Wrapf(err, "cannot list %ss", kind)
+		}
+		for _, o := range l.Items {
+			res = append(res, o.Object)
+		}
+	}
+	b, err := yaml.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": res})
+	return b, errors.Wrap(err, "cannot marshal the providers")
+}
+
+// ContainerLogs are the logs of a container, or the error they could not be
+// collected with.
+type ContainerLogs struct {
+	Logs []byte
+	Err  error
+}
+
+// ProviderLogs returns the logs of the containers of the provider pods since
+// the specified time, keyed by namespace/pod/container. The logs of a
+// container that cannot be collected are recorded with their error, so that
+// they do not prevent collecting the logs of the other containers.
+func (c *Client) ProviderLogs(ctx context.Context, since time.Time) (map[string]ContainerLogs, error) {
+	if c.pods == nil {
+		return nil, errors.New("cannot get the pod logs without a pods client")
+	}
+	pods := &corev1.PodList{}
+	if err := c.kube.List(ctx, pods, client.HasLabels{pkgv1.LabelProvider}); err != nil {
+		return nil, errors.Wrap(err, "cannot list the provider pods")
+	}
+	res := map[string]ContainerLogs{}
+	st := metav1.NewTime(since)
+	for _, p := range pods.Items {
+		for _, ct := range p.Spec.Containers {
+			b, err := c.logs(ctx, p.Namespace, p.Name, &corev1.PodLogOptions{Container: ct.Name, SinceTime: &st})
+			res[p.Namespace+"/"+p.Name+"/"+ct.Name] = ContainerLogs{Logs: b, Err: errors.Wrapf(err, "cannot collect the logs of container %s", ct.Name)}
+		}
+	}
+	return res, nil
+}
+
+func (c *Client) logs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) ([]byte, error) {
+	s, err := c.pods.Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get the logs of pod %s/%s", namespace, name)
+	}
+	defer s.Close() //nolint:errcheck // Read only stream, closing errors are not relevant.
+	b, err := io.ReadAll(s)
+	return b, errors.Wrapf(err, "cannot read the logs of pod %s/%s", namespace, name)
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	restfake "k8s.io/client-go/rest/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestEvents(t *testing.T) {
+	event := func(name, kind, object string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: kind, Name: object},
+			Reason:         "CreatedExternalResource",
+		}
+	}
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		event("bucket.1", "Bucket", "example"),
+		event("bucket.2", "Bucket", "other"),
+		event("policy.1", "BucketPolicy", "example"),
+	).Build()
+	r := config.Resource{Name: "example", KindGroup: "bucket.s3.aws.upbound.io", APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket"}
+	got, err := NewWithClient(kube).Events(context.Background(), r)
+	if err != nil {
+		t.Fatalf("Events(...): %v", err)
+	}
+	l := &corev1.EventList{}
+	if err := yaml.Unmarshal(got, l); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range l.Items {
+		names = append(names, e.Name)
+	}
+	if diff := cmp.Diff([]string{"bucket.1"}, names); diff != "" {
+		t.Errorf("Events(...): -want, +got:\n%s", diff)
+	}
+}
+
+// failingLogs is a pods client whose log streams fail for the specified
+// container.
+type failingLogs struct {
+	corev1client.PodsGetter
+	container string
+}
+
+func (f failingLogs) Pods(namespace string) corev1client.PodInterface {
+	return failingLogsPods{PodInterface: f.PodsGetter.Pods(namespace), container: f.container}
+}
+
+type failingLogsPods struct {
+	corev1client.PodInterface
+	container string
+}
+
+func (f failingLogsPods) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
+	if opts.Container != f.container {
+		return f.PodInterface.GetLogs(name, opts)
+	}
+	c := &restfake.RESTClient{
+		NegotiatedSerializer: clientgoscheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(*http.Request) (*http.Response, error) {
+			return nil, errBoom
+		}),
+	}
+	return c.Get()
+}
+
+var errBoom = errors.New("boom")
+
+func TestProviderLogs(t *testing.T) {
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "crossplane-system", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "package-runtime"}, {Name: "sidecar"}}},
+		}
+	}
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		pod("provider-aws-s3-abc", map[string]string{"pkg.crossplane.io/provider": "provider-aws-s3"}),
+		pod("crossplane-def", map[string]string{"app": "crossplane"}),
+	).Build()
+	c := NewWithClient(kube)
+	c.pods = failingLogs{PodsGetter: kubefake.NewClientset().CoreV1(), container: "sidecar"}
+	got, err := c.ProviderLogs(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("ProviderLogs(...): %v", err)
+	}
+	want := map[string]ContainerLogs{
+		"crossplane-system/provider-aws-s3-abc/package-runtime": {Logs: []byte("fake logs")},
+		"crossplane-system/provider-aws-s3-abc/sidecar":         {Err: errBoom},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("ProviderLogs(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/graph"
+)
+
+// annotationKeyTest is the annotation uptest marks the tested managed
+// resources with.
+const annotationKeyTest = "upjet.upbound.io/test"
+
+// GCOptions select the managed resources to garbage collect.
+type GCOptions struct {
+	// MinAge is the minimum age of the selected resources.
+	MinAge time.Duration
+	// RunID selects only the resources of the run, if set.
+	RunID string
+	// Groups select only the resources whose API group is one of or a
+	// subgroup of the groups, e.g. aws.upbound.io selects
+	// s3.aws.upbound.io, if set.
+	Groups []string
+}
+
+// Leftovers returns the managed resources created by uptest runs that are
+// selected by the options. The resources are recognized by the annotation
+// uptest marks them with or the run ID label.
+func (c *Client) Leftovers(ctx context.Context, opts GCOptions) ([]*unstructured.Unstructured, error) {
+	crds := &extv1.CustomResourceDefinitionList{}
+	if err := c.kube.List(ctx, crds); err != nil {
+		return nil, errors.Wrap(err, "cannot list CustomResourceDefinitions")
+	}
+	var listOpts []client.ListOption
+	if opts.RunID != "" {
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(labels.Set{config.LabelKeyRunID: opts.RunID})})
+	}
+	cutoff := time.Now().Add(-opts.MinAge)
+	var res []*unstructured.Unstructured
+	for _, crd := range crds.Items {
+		if !hasCategory(crd, []string{"managed"}) || !inGroups(crd.Spec.Group, opts.Groups) {
+			continue
+		}
+		gvk, ok := storageVersionKind(crd)
+		if !ok {
+			continue
+		}
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.kube.List(ctx, l, listOpts...); err != nil {
+			return nil, errors.Wrapf(err, "cannot list %s", gvk.GroupKind())
+		}
+		for i := range l.Items {
+			o := &l.Items[i]
+			if !markedByUptest(o) || o.GetCreationTimestamp().After(cutoff) {
+				continue
+			}
+			res = append(res, o)
+		}
+	}
+	return res, nil
+}
+
+// DeletionStages groups the objects into stages in dependency order, so that
+// the objects of a stage are deleted before the objects they depend on in
+// the later stages. All objects are in a single stage if their dependencies
+// have a cycle.
+func DeletionStages(ctx context.Context, objs []*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	tiers, err := graph.Tiers(objs)
+	if err != nil {
+		slog.WarnContext(ctx, "Cannot resolve the dependencies of the objects, deleting them in a single stage", "error", err)
+		tiers = make([]int, len(objs))
+	}
+	maxTier := -1
+	for _, t := range tiers {
+		maxTier = max(maxTier, t)
+	}
+	res := make([][]*unstructured.Unstructured, maxTier+1)
+	for i, o := range objs {
+		res[maxTier-tiers[i]] = append(res[maxTier-tiers[i]], o)
+	}
+	return res
+}
+
+func markedByUptest(o *unstructured.Unstructured) bool {
+	if o.GetAnnotations()[annotationKeyTest] == "true" {
+		return true
+	}
+	_, ok := o.GetLabels()[config.LabelKeyRunID]
+	return ok
+}
+
+func inGroups(group string, groups []string) bool {
+	if len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		if group == g || strings.HasSuffix(group, "."+g) {
+			return true
+		}
+	}
+	return false
+}
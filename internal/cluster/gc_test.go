@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func leftover(apiVersion, kind, name, runID string, tested bool, age time.Duration) *unstructured.Unstructured {
+	u := object(apiVersion, kind, "", name, runID)
+	if tested {
+		u.SetAnnotations(map[string]string{"upjet.upbound.io/test": "true"})
+	}
+	u.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-age)))
+	return u
+}
+
+func TestLeftovers(t *testing.T) {
+	objs := []client.Object{
+		categorized("s3.aws.upbound.io", "Bucket", "crossplane", "managed", "aws"),
+		categorized("storage.gcp.upbound.io", "Bucket", "crossplane", "managed", "gcp"),
+		categorized("example.org", "XNetwork", "crossplane", "composite"),
+		leftover("s3.aws.upbound.io/v1beta1", "Bucket", "old-tested", "", true, 10*time.Hour),
+		leftover("s3.aws.upbound.io/v1beta1", "Bucket", "old-run", "abcde12345", false, 10*time.Hour),
+		leftover("s3.aws.upbound.io/v1beta1", "Bucket", "old-other-run", "fghij67890", false, 10*time.Hour),
+		leftover("s3.aws.upbound.io/v1beta1", "Bucket", "new-tested", "", true, time.Minute),
+		leftover("s3.aws.upbound.io/v1beta1", "Bucket", "old-untested", "", false, 10*time.Hour),
+		leftover("storage.gcp.upbound.io/v1beta1", "Bucket", "old-gcp", "", true, 10*time.Hour),
+		leftover("example.org/v1beta1", "XNetwork", "old-composite", "abcde12345", true, 10*time.Hour),
+	}
+	tests := map[string]struct {
+		opts GCOptions
+		want []string
+	}{
+		"OlderThan": {
+			opts: GCOptions{MinAge: time.Hour},
+			want: []string{"old-gcp", "old-other-run", "old-run", "old-tested"},
+		},
+		"RunID": {
+			opts: GCOptions{MinAge: time.Hour, RunID: "abcde12345"},
+			want: []string{"old-run"},
+		},
+		"Group": {
+			opts: GCOptions{MinAge: time.Hour, Groups: []string{"gcp.upbound.io"}},
+			want: []string{"old-gcp"},
+		},
+		"NoMinAge": {
+			opts: GCOptions{Groups: []string{"s3.aws.upbound.io"}},
+			want: []string{"new-tested", "old-other-run", "old-run", "old-tested"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := extv1.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			kube := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+			got, err := NewWithClient(kube).Leftovers(context.Background(), tc.opts)
+			if err != nil {
+				t.Fatalf("Leftovers(...): %v", err)
+			}
+			names := make([]string, 0, len(got))
+			for _, o := range got {
+				names = append(names, o.GetName())
+			}
+			sort.Strings(names)
+			if diff := cmp.Diff(tc.want, names); diff != "" {
+				t.Errorf("Leftovers(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeletionStages(t *testing.T) {
+	vpc := object("ec2.aws.upbound.io/v1beta1", "VPC", "", "vpc", "")
+	subnet := object("ec2.aws.upbound.io/v1beta1", "Subnet", "", "subnet", "")
+	_ = unstructured.SetNestedField(subnet.Object, "vpc", "spec", "forProvider", "vpcIdRef", "name")
+	instance := object("ec2.aws.upbound.io/v1beta1", "Instance", "", "instance", "")
+	_ = unstructured.SetNestedField(instance.Object, "subnet", "spec", "forProvider", "subnetIdRef", "name")
+	bucket := object("s3.aws.upbound.io/v1beta1", "Bucket", "", "bucket", "")
+
+	got := DeletionStages(context.Background(), []*unstructured.Unstructured{vpc, subnet, instance, bucket})
+	names := make([][]string, len(got))
+	for i, s := range got {
+		for _, o := range s {
+			names[i] = append(names[i], o.GetName())
+		}
+	}
+	want := [][]string{{"instance"}, {"subnet"}, {"vpc", "bucket"}}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("DeletionStages(...): -want, +got:\n%s", diff)
+	}
+}
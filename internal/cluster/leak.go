@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+// errExternalResourceNotExist is the message of the ReconcileError
+// condition of an Observe-only managed resource whose external resource
+// does not exist.
+const errExternalResourceNotExist = "external resource does not exist"
+
+// LeakStatus is the status of the external resource of a deleted managed
+// resource.
+type LeakStatus string
+
+const (
+	// LeakStatusDeleted means the external resource does not exist.
+	LeakStatusDeleted LeakStatus = "Deleted"
+	// LeakStatusLeaked means the external resource still exists.
+	LeakStatusLeaked LeakStatus = "Leaked"
+	// LeakStatusUnknown means it could not be determined whether the
+	// external resource exists.
+	LeakStatusUnknown LeakStatus = "Unknown"
+)
+
+// ExternalRef is the information needed to observe the external resource of
+// a managed resource after the managed resource is deleted.
+type ExternalRef struct {
+	Resource          config.Resource
+	ExternalName      string
+	ProviderConfigRef map[string]interface{}
+	// ForProvider is the spec.forProvider of the managed resource, which has
+	// the parameters needed to observe the external resource, e.g. its
+	// region.
+	ForProvider map[string]interface{}
+	// RunID is the run ID the managed resource was labelled with, if any.
+	RunID string
+}
+
+// LeakResult is the result of checking whether the external resource of a
+// deleted managed resource still exists.
+type LeakResult struct {
+	Ref    ExternalRef
+	Status LeakStatus
+}
+
+// String returns a summary of the result.
+func (r LeakResult) String() string {
+	return fmt.Sprintf("%s/%s (external name %q): %s", r.Ref.Resource.KindGroup, r.Ref.Resource.Name, r.Ref.ExternalName, r.Status)
+}
+
+// RecordExternalRefs records the external names of the specified managed
+// resources, so that their external resources can be observed after they
+// are deleted. The resources that do not exist or have no external name are
+// skipped.
+func (c *Client) RecordExternalRefs(ctx context.Context, resources []config.Resource) ([]ExternalRef, error) {
+	var refs []ExternalRef
+	for _, r := range resources {
+		if !r.IsManaged() {
+			continue
+		}
+		u, err := c.get(ctx, r)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := meta.GetExternalName(u)
+		if name == "" {
+			continue
+		}
+		pc, _, _ := unstructured.NestedMap(u.Object, "spec", "providerConfigRef")
+		fp, _, _ := unstructured.NestedMap(u.Object, "spec", "forProvider")
+		refs = append(refs, ExternalRef{Resource: r, ExternalName: name, ProviderConfigRef: pc, ForProvider: fp, RunID: u.GetLabels()[config.LabelKeyRunID]})
+	}
+	return refs, nil
+}
+
+// CheckLeaks checks whether the external resources of the deleted managed
+// resources still exist. For each of them, an Observe-only managed resource
+// with the recorded external name is created, which reports whether the
+// external resource exists. They are all observed together until each of
+// them reports a status or the timeout expires, and then deleted without
+// affecting the external resources. The error of the context is returned if
+// it's canceled before the check completes.
+func (c *Client) CheckLeaks(ctx context.Context, refs []ExternalRef, timeout, interval time.Duration) (res []LeakResult, err error) {
+	probes := make([]*unstructured.Unstructured, 0, len(refs))
+	// The Observe-only managed resources are deleted even if the check is
+	// interrupted.
+	defer func() {
+		for _, o := range probes {
+			if derr := c.kube.Delete(context.WithoutCancel(ctx), o); resource.IgnoreNotFound(derr) != nil {
+				err = errors.Join(err, errors.Wrapf(derr, "cannot delete the Observe-only %s/%s", o.GetKind(), o.GetName()))
+			}
+		}
+	}()
+	res = make([]LeakResult, 0, len(refs))
+	for _, ref := range refs {
+		o := observeOnly(ref)
+		if err := c.kube.Create(ctx, o); err != nil {
+			return nil, errors.Wrapf(err, "cannot create the Observe-only %s/%s", ref.Resource.KindGroup, ref.Resource.Name)
+		}
+		probes = append(probes, o)
+		res = append(res, LeakResult{Ref: ref, Status: LeakStatusUnknown})
+	}
+	err = wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		done := true
+		for i := range res {
+			if res[i].Status != LeakStatusUnknown {
+				continue
+			}
+			u, err := c.get(ctx, res[i].Ref.Resource)
+			if err != nil {
+				return false, err
+			}
+			res[i].Status = leakStatus(u)
+			done = done && res[i].Status != LeakStatusUnknown
+		}
+		return done, nil
+	})
+	// The statuses that are still unknown when the timeout expires are
+	// reported as such, whereas an interrupted check is not reported at all.
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "leak check was cancelled")
+	}
+	if err != nil && !wait.Interrupted(err) {
+		return nil, errors.Wrap(err, "cannot observe the external resources")
+	}
+	return res, nil
+}
+
+// observeOnly returns an Observe-only managed resource for the referenced
+// external resource.
+func observeOnly(ref ExternalRef) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.Resource.APIVersion, ref.Resource.Kind))
+	u.SetName(ref.Resource.Name)
+	u.SetNamespace(ref.Resource.Namespace)
+	u.SetAnnotations(map[string]string{
+		meta.AnnotationKeyExternalName: ref.ExternalName,
+		annotationKeyTest:              "true",
+	})
+	if ref.RunID != "" {
+		u.SetLabels(map[string]string{config.LabelKeyRunID: ref.RunID})
+	}
+	_ = unstructured.SetNestedStringSlice(u.Object, []string{string(xpv1.ManagementActionObserve)}, "spec", "managementPolicies")
+	if ref.ProviderConfigRef != nil {
+		_ = unstructured.SetNestedMap(u.Object, ref.ProviderConfigRef, "spec", "providerConfigRef")
+	}
+	if ref.ForProvider != nil {
+		_ = unstructured.SetNestedMap(u.Object, ref.ForProvider, "spec", "forProvider")
+	}
+	return u
+}
+
+// leakStatus returns the status of the external resource observed by the
+// specified Observe-only managed resource.
+func leakStatus(u *unstructured.Unstructured) LeakStatus {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch {
+		case m["type"] == string(xpv1.TypeReady) && m["status"] == "True":
+			return LeakStatusLeaked
+		case m["type"] == string(xpv1.TypeSynced) && m["status"] == "False":
+			if msg, _ := m["message"].(string); strings.Contains(msg, errExternalResourceNotExist) {
+				return LeakStatusDeleted
+			}
+		}
+	}
+	return LeakStatusUnknown
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestLeakStatus(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		want     LeakStatus
+	}{
+		"Leaked": {
+			manifest: `status:
+  conditions:
+  - type: Synced
+    status: "True"
+  - type: Ready
+    status: "True"
+`,
+			want: LeakStatusLeaked,
+		},
+		"Deleted": {
+			manifest: `status:
+  conditions:
+  - type: Synced
+    status: "False"
+    reason: ReconcileError
+    message: 'cannot observe external resource: external resource does not exist'
+`,
+			want: LeakStatusDeleted,
+		},
+		"OtherError": {
+			manifest: `status:
+  conditions:
+  - type: Synced
+    status: "False"
+    reason: ReconcileError
+    message: 'cannot observe external resource: access denied'
+`,
+			want: LeakStatusUnknown,
+		},
+		"NotObservedYet": {
+			manifest: `metadata:
+  name: example
+`,
+			want: LeakStatusUnknown,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(tc.manifest), &u.Object); err != nil {
+				t.Fatalf("cannot unmarshal manifest: %v", err)
+			}
+			if got := leakStatus(u); got != tc.want {
+				t.Errorf("leakStatus(...): want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRecordExternalRefs(t *testing.T) {
+	bucket := config.Resource{
+		Name:       "example",
+		KindGroup:  "bucket.s3.aws.upbound.io",
+		APIVersion: "s3.aws.upbound.io/v1beta1",
+		Kind:       "Bucket",
+		Category:   config.CategoryManaged,
+	}
+	vpc := config.Resource{
+		Name:       "example",
+		KindGroup:  "vpc.ec2.aws.upbound.io",
+		APIVersion: "ec2.aws.upbound.io/v1beta1",
+		Kind:       "VPC",
+		Category:   config.CategoryManaged,
+	}
+	objs := map[string]string{
+		"bucket": `apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: example
+  labels:
+    uptest.upbound.io/run-id: abcde12345
+  annotations:
+    crossplane.io/external-name: example-bucket-abc
+spec:
+  providerConfigRef:
+    name: default
+  forProvider:
+    region: us-west-1
+    forceDestroy: true
+`,
+		"vpc": `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+`,
+	}
+	b := fake.NewClientBuilder().WithScheme(runtime.NewScheme())
+	for _, m := range objs {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &u.Object); err != nil {
+			t.Fatalf("cannot unmarshal manifest: %v", err)
+		}
+		b = b.WithObjects(u)
+	}
+	c := NewWithClient(b.Build())
+	missing := bucket
+	missing.Name = "missing"
+	got, err := c.RecordExternalRefs(context.Background(), []config.Resource{bucket, vpc, missing, {Name: "creds", Category: config.CategoryKubernetes}})
+	if err != nil {
+		t.Fatalf("RecordExternalRefs(...): %v", err)
+	}
+	want := []ExternalRef{{
+		Resource:          bucket,
+		ExternalName:      "example-bucket-abc",
+		ProviderConfigRef: map[string]interface{}{"name": "default"},
+		ForProvider:       map[string]interface{}{"region": "us-west-1", "forceDestroy": true},
+		RunID:             "abcde12345",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RecordExternalRefs(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	ref := ExternalRef{
+		Resource: config.Resource{
+			Name:       "example",
+			Namespace:  "default",
+			APIVersion: "s3.aws.m.upbound.io/v1beta1",
+			Kind:       "Bucket",
+		},
+		ExternalName:      "example-bucket-abc",
+		ProviderConfigRef: map[string]interface{}{"kind": "ProviderConfig", "name": "default"},
+		ForProvider:       map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"key": "value"}},
+		RunID:             "abcde12345",
+	}
+	want := map[string]interface{}{
+		"apiVersion": "s3.aws.m.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata": map[string]interface{}{
+			"name":      "example",
+			"namespace": "default",
+			"labels": map[string]interface{}{
+				"uptest.upbound.io/run-id": "abcde12345",
+			},
+			"annotations": map[string]interface{}{
+				"crossplane.io/external-name": "example-bucket-abc",
+				"upjet.upbound.io/test":       "true",
+			},
+		},
+		"spec": map[string]interface{}{
+			"managementPolicies": []interface{}{"Observe"},
+			"providerConfigRef":  map[string]interface{}{"kind": "ProviderConfig", "name": "default"},
+			"forProvider":        map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"key": "value"}},
+		},
+	}
+	if diff := cmp.Diff(want, observeOnly(ref).Object); diff != "" {
+		t.Errorf("observeOnly(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestCheckLeaks(t *testing.T) {
+	ref := func(name string) ExternalRef {
+		return ExternalRef{
+			Resource: config.Resource{
+				Name:       name,
+				KindGroup:  "bucket.s3.aws.upbound.io",
+				APIVersion: "s3.aws.upbound.io/v1beta1",
+				Kind:       "Bucket",
+				Category:   config.CategoryManaged,
+			},
+			ExternalName: name + "-abc",
+		}
+	}
+	kube := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	c := NewWithClient(kube)
+	refs := []ExternalRef{ref("first"), ref("second")}
+	// The fake client does not run the provider, so the Observe-only
+	// resources never report a status and the check times out.
+	got, err := c.CheckLeaks(context.Background(), refs, 50*time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("CheckLeaks(...): %v", err)
+	}
+	want := []LeakResult{{Ref: refs[0], Status: LeakStatusUnknown}, {Ref: refs[1], Status: LeakStatusUnknown}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CheckLeaks(...): -want, +got:\n%s", diff)
+	}
+	for _, r := range refs {
+		if _, err := c.get(context.Background(), r.Resource); !kerrors.IsNotFound(err) {
+			t.Errorf("CheckLeaks(...): want the Observe-only %s to be deleted, got %v", r.Resource.Name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = c.CheckLeaks(ctx, refs, time.Minute, 10*time.Millisecond)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("CheckLeaks(...): want the error of the canceled context, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("CheckLeaks(...): want no results of the canceled check, got %v", got)
+	}
+	for _, r := range refs {
+		if _, err := c.get(context.Background(), r.Resource); !kerrors.IsNotFound(err) {
+			t.Errorf("CheckLeaks(...): want the Observe-only %s of the canceled check to be deleted, got %v", r.Resource.Name, err)
+		}
+	}
+}
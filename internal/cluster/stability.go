@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+// reasonUpdatedExternalResource is the reason of the event emitted by the
+// managed reconciler each time it updates an external resource.
+const reasonUpdatedExternalResource = "UpdatedExternalResource"
+
+// StabilityResult is the result of watching a managed resource for changes
+// after it became ready.
+type StabilityResult struct {
+	Resource config.Resource
+	// GenerationChanges is the number of times metadata.generation
+	// increased.
+	GenerationChanges int64
+	// UpdateEvents is the number of times the external resource was
+	// updated.
+	UpdateEvents int32
+	// FieldChanges is the number of times each field of spec.forProvider
+	// was observed to change.
+	FieldChanges map[string]int
+}
+
+// Stable reports whether the resource settled during the window. A single
+// change is tolerated, as the late-initialization of the resource may
+// happen after it became ready, but any further change means the resource
+// is in an update loop.
+func (r StabilityResult) Stable() bool {
+	if r.GenerationChanges > 1 || r.UpdateEvents > 1 {
+		return false
+	}
+	for _, n := range r.FieldChanges {
+		if n > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a summary of the changes of the resource.
+func (r StabilityResult) String() string {
+	s := fmt.Sprintf("%s/%s: generation increased %d times, %d update events", r.Resource.KindGroup, r.Resource.Name, r.GenerationChanges, r.UpdateEvents)
+	fields := make([]string, 0, len(r.FieldChanges))
+	for f := range r.FieldChanges {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		s += fmt.Sprintf(", %s changed %d times", f, r.FieldChanges[f])
+	}
+	return s
+}
+
+// watchedResource is the state of a resource being watched for changes.
+type watchedResource struct {
+	result      StabilityResult
+	generation  int64
+	forProvider map[string]interface{}
+	events      int32
+}
+
+// CheckStability watches the specified managed resources for the specified
+// window, polling them at the specified interval, and returns the changes
+// observed for each of them.
+func (c *Client) CheckStability(ctx context.Context, resources []config.Resource, window, interval time.Duration) ([]StabilityResult, error) {
+	watched := make([]*watchedResource, 0, len(resources))
+	for _, r := range resources {
+		if !r.IsManaged() {
+			continue
+		}
+		u, err := c.get(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		events, err := c.updateEvents(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		forProvider, _, _ := unstructured.NestedMap(u.Object, "spec", "forProvider")
+		watched = append(watched, &watchedResource{
+			result:      StabilityResult{Resource: r, FieldChanges: map[string]int{}},
+			generation:  u.GetGeneration(),
+			forProvider: forProvider,
+			events:      events,
+		})
+	}
+
+	t := time.NewTimer(window)
+	defer t.Stop()
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "stability check was cancelled")
+		case <-t.C:
+			done = true
+		case <-tick.C:
+		}
+		for _, w := range watched {
+			if err := c.observe(ctx, w); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res := make([]StabilityResult, 0, len(watched))
+	for _, w := range watched {
+		events, err := c.updateEvents(ctx, w.result.Resource)
+		if err != nil {
+			return nil, err
+		}
+		w.result.UpdateEvents = events - w.events
+		res = append(res, w.result)
+	}
+	return res, nil
+}
+
+// observe records the changes of the watched resource since it was last
+// observed.
+func (c *Client) observe(ctx context.Context, w *watchedResource) error {
+	u, err := c.get(ctx, w.result.Resource)
+	if err != nil {
+		return err
+	}
+	if g := u.GetGeneration(); g > w.generation {
+		w.result.GenerationChanges += g - w.generation
+		w.generation = g
+	}
+	forProvider, _, _ := unstructured.NestedMap(u.Object, "spec", "forProvider")
+	for _, f := range changedFields("spec.forProvider", w.forProvider, forProvider) {
+		w.result.FieldChanges[f]++
+	}
+	w.forProvider = forProvider
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, r config.Resource) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.FromAPIVersionAndKind(r.APIVersion, r.Kind))
+	err := c.kube.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, u)
+	return u, errors.Wrapf(err, "cannot get %s/%s", r.KindGroup, r.Name)
+}
+
+// updateEvents returns the number of times the external resource of the
+// specified resource was updated, according to its events.
+func (c *Client) updateEvents(ctx context.Context, r config.Resource) (int32, error) {
+	l := &corev1.EventList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return 0, errors.Wrap(err, "cannot list events")
+	}
+	var n int32
+	for _, e := range l.Items {
+		o := e.InvolvedObject
+		if e.Reason != reasonUpdatedExternalResource || o.APIVersion != r.APIVersion || o.Kind != r.Kind || o.Name != r.Name || o.Namespace != r.Namespace {
+			continue
+		}
+		n += max(e.Count, 1)
+	}
+	return n, nil
+}
+
+// changedFields returns the paths of the leaf fields that differ between
+// the specified objects.
+func changedFields(prefix string, before, after map[string]interface{}) []string {
+	var res []string
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	for k := range keys {
+		b, a := before[k], after[k]
+		bm, bok := b.(map[string]interface{})
+		am, aok := a.(map[string]interface{})
+		if bok && aok {
+			res = append(res, changedFields(prefix+"."+k, bm, am)...)
+			continue
+		}
+		if !reflect.DeepEqual(a, b) {
+			res = append(res, prefix+"."+k)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
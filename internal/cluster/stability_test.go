@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestChangedFields(t *testing.T) {
+	type args struct {
+		before map[string]interface{}
+		after  map[string]interface{}
+	}
+	tests := map[string]struct {
+		args args
+		want []string
+	}{
+		"NoChange": {
+			args: args{
+				before: map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"a": "b"}},
+				after:  map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"a": "b"}},
+			},
+		},
+		"NestedChange": {
+			args: args{
+				before: map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"a": "b"}},
+				after:  map[string]interface{}{"region": "us-west-1", "tags": map[string]interface{}{"a": "c"}},
+			},
+			want: []string{"spec.forProvider.tags.a"},
+		},
+		"AddedAndRemoved": {
+			args: args{
+				before: map[string]interface{}{"region": "us-west-1"},
+				after:  map[string]interface{}{"acl": "private", "versioning": []interface{}{"x"}},
+			},
+			want: []string{"spec.forProvider.acl", "spec.forProvider.region", "spec.forProvider.versioning"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := changedFields("spec.forProvider", tc.args.before, tc.args.after)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("changedFields(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStabilityResultStable(t *testing.T) {
+	tests := map[string]struct {
+		r    StabilityResult
+		want bool
+	}{
+		"NoChanges": {
+			r:    StabilityResult{},
+			want: true,
+		},
+		"LateInitialization": {
+			r:    StabilityResult{GenerationChanges: 1, UpdateEvents: 1, FieldChanges: map[string]int{"spec.forProvider.acl": 1}},
+			want: true,
+		},
+		"GenerationLoop": {
+			r: StabilityResult{GenerationChanges: 4},
+		},
+		"UpdateLoop": {
+			r: StabilityResult{UpdateEvents: 3},
+		},
+		"FieldLoop": {
+			r: StabilityResult{FieldChanges: map[string]int{"spec.forProvider.tags.a": 2}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.r.Stable(); got != tc.want {
+				t.Errorf("Stable(): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckStability(t *testing.T) {
+	r := config.Resource{
+		Name:       "example",
+		KindGroup:  "bucket.s3.aws.upbound.io",
+		APIVersion: "s3.aws.upbound.io/v1beta1",
+		Kind:       "Bucket",
+		Category:   config.CategoryManaged,
+	}
+	mr := &unstructured.Unstructured{}
+	mr.SetAPIVersion(r.APIVersion)
+	mr.SetKind(r.Kind)
+	mr.SetName(r.Name)
+	mr.SetGeneration(1)
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Name:       r.Name,
+		},
+		Reason: reasonUpdatedExternalResource,
+		Count:  3,
+	}
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := NewWithClient(fake.NewClientBuilder().WithScheme(s).WithObjects(mr, event).Build())
+	got, err := c.CheckStability(context.Background(), []config.Resource{r, {Name: "creds", Category: config.CategoryKubernetes}}, time.Millisecond, time.Hour)
+	if err != nil {
+		t.Fatalf("CheckStability(...): %v", err)
+	}
+	want := []StabilityResult{{Resource: r, FieldChanges: map[string]int{}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CheckStability(...): -want, +got:\n%s", diff)
+	}
+}
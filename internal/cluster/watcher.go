@@ -0,0 +1,441 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+// Condition is the observed state of a status condition of a resource.
+type Condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// ResourceState is the observed state of a tested resource.
+type ResourceState struct {
+	Resource   config.Resource
+	Exists     bool
+	Conditions []Condition
+	// LastEvent is the last Warning event of the resource.
+	LastEvent     string
+	LastEventTime time.Time
+}
+
+// Condition returns the condition of the specified type, if observed.
+func (s ResourceState) Condition(t string) (Condition, bool) {
+	for _, c := range s.Conditions {
+		if c.Type == t {
+			return c, true
+		}
+	}
+	return Condition{}, false
+}
+
+// Watcher watches the tested resources and their Warning events through
+// informers, and logs the condition transitions and the Warning events as
+// they happen. A summary of the resources is logged periodically, only if
+// they changed since the last summary.
+type Watcher struct {
+	mu      sync.Mutex
+	started time.Time
+	states  map[string]*ResourceState
+	order   []string
+	changed bool
+	logger  *slog.Logger
+	// ctx is the context the watcher is started with, which carries the
+	// fields of the logged messages, e.g. the run ID.
+	ctx context.Context
+	// history is the logger of the history file of each resource, keyed by
+	// state key, if the histories are recorded.
+	history map[string]*slog.Logger
+	files   []*os.File
+}
+
+// NewWatcher returns a Watcher for the specified resources. The watcher does
+// not watch anything until it's started with Client.Watch.
+func NewWatcher(resources []config.Resource) *Watcher {
+	w := &Watcher{
+		started: time.Now(),
+		states:  make(map[string]*ResourceState, len(resources)),
+		logger:  slog.Default(),
+		ctx:     context.Background(),
+	}
+	for _, r := range resources {
+		k := stateKey(r.APIVersion, r.Kind, r.Namespace, r.Name)
+		if _, ok := w.states[k]; ok {
+			continue
+		}
+		w.states[k] = &ResourceState{Resource: r}
+		w.order = append(w.order, k)
+	}
+	return w
+}
+
+// RecordHistory records the condition transitions and the Warning events of
+// each resource also in a separate file in the specified directory, named
+// after the resource, so that the history of a single resource can be
+// inspected without the messages about the others. The files are closed by
+// Close.
+func (w *Watcher) RecordHistory(dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil { //nolint:gosec // directory permissions are not critical here
+		return errors.Wrapf(err, "cannot create the history directory %s", dir)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.history = make(map[string]*slog.Logger, len(w.order))
+	for _, k := range w.order {
+		path := filepath.Join(dir, HistoryFileName(w.states[k].Resource))
+		f, err := os.Create(filepath.Clean(path))
+		if err != nil {
+			return errors.Wrapf(err, "cannot create the history file %s", path)
+		}
+		w.files = append(w.files, f)
+		w.history[k] = slog.New(logging.NewHandler(f, slog.LevelDebug, logging.FormatText))
+	}
+	return nil
+}
+
+// Close closes the history files of the resources.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var err error
+	for _, f := range w.files {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = errors.Wrapf(cerr, "cannot close the history file %s", f.Name())
+		}
+	}
+	w.files, w.history = nil, nil
+	return err
+}
+
+// HistoryFileName returns the name of the history file of the resource.
+func HistoryFileName(r config.Resource) string {
+	if r.Namespace != "" {
+		return r.KindGroup + "-" + r.Namespace + "-" + r.Name + ".log"
+	}
+	return r.KindGroup + "-" + r.Name + ".log"
+}
+
+// Snapshot returns the observed states of the resources, in the order they
+// were specified.
+func (w *Watcher) Snapshot() []ResourceState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res := make([]ResourceState, 0, len(w.order))
+	for _, k := range w.order {
+		s := *w.states[k]
+		s.Conditions = append([]Condition(nil), s.Conditions...)
+		res = append(res, s)
+	}
+	return res
+}
+
+// watchBackoff is the backoff of the retries to watch the kinds that cannot
+// be mapped to their resources yet, e.g. since their CRDs are installed by
+// the tested resources themselves.
+var watchBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: math.MaxInt32, Cap: time.Minute}
+
+// Watch starts the informers of the watcher, which run until the context is
+// done. If runID is set, only the resources labelled with it are listed, so
+// that the other resources of the same kinds are not cached. The kinds that
+// cannot be mapped to their resources yet are watched as soon as they can,
+// and only the Warning events in the namespaces of the resources are watched.
+// A summary is logged at most once every summaryInterval.
+func (c *Client) Watch(ctx context.Context, w *Watcher, runID string, summaryInterval time.Duration) error {
+	if c.dynamic == nil {
+		return errors.New("cannot watch the resources without a dynamic client")
+	}
+	w.mu.Lock()
+	w.ctx = ctx
+	w.mu.Unlock()
+	resources := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamic, 0, metav1.NamespaceAll, func(o *metav1.ListOptions) {
+		if runID != "" {
+			o.LabelSelector = labels.SelectorFromSet(labels.Set{config.LabelKeyRunID: runID}).String()
+		}
+	})
+	seen := map[schema.GroupVersionKind]bool{}
+	namespaces := map[string]bool{}
+	for _, s := range w.Snapshot() {
+		// The events of the cluster scoped resources are recorded in the
+		// default namespace.
+		namespaces[eventNamespace(s.Resource)] = true
+		gvk := schema.FromAPIVersionAndKind(s.Resource.APIVersion, s.Resource.Kind)
+		if seen[gvk] {
+			continue
+		}
+		seen[gvk] = true
+		mapped, err := c.watchKind(w, resources, gvk)
+		if err != nil {
+			return err
+		}
+		if !mapped {
+			go c.retryWatchKind(ctx, w, resources, gvk)
+		}
+	}
+
+	for ns := range namespaces {
+		events := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamic, 0, ns, func(o *metav1.ListOptions) {
+			o.FieldSelector = fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String()
+		})
+		if _, err := events.ForResource(corev1.SchemeGroupVersion.WithResource("events")).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { w.observeEvent(obj) },
+			UpdateFunc: func(_, obj interface{}) { w.observeEvent(obj) },
+		}); err != nil {
+			return errors.Wrapf(err, "cannot watch the events in namespace %s", ns)
+		}
+		events.Start(ctx.Done())
+	}
+
+	resources.Start(ctx.Done())
+	go w.summarize(ctx, summaryInterval)
+	return nil
+}
+
+// watchKind adds the informer of the resources of the specified kind to the
+// informer factory. It returns false if the kind cannot be mapped to its
+// resource yet.
+func (c *Client) watchKind(w *Watcher, f dynamicinformer.DynamicSharedInformerFactory, gvk schema.GroupVersionKind) (bool, error) {
+	m, err := c.kube.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		w.logger.DebugContext(w.ctx, "Cannot get the REST mapping of the kind, retrying later", "kind", gvk.String(), "error", err)
+		return false, nil
+	}
+	if _, err := f.ForResource(m.Resource).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { w.observe(obj, false) },
+		UpdateFunc: func(_, obj interface{}) { w.observe(obj, false) },
+		DeleteFunc: func(obj interface{}) { w.observe(obj, true) },
+	}); err != nil {
+		return false, errors.Wrapf(err, "cannot watch %s", m.Resource)
+	}
+	return true, nil
+}
+
+// retryWatchKind retries to watch the resources of the specified kind with
+// backoff until it succeeds or the context is done, and starts the informer
+// then.
+func (c *Client) retryWatchKind(ctx context.Context, w *Watcher, f dynamicinformer.DynamicSharedInformerFactory, gvk schema.GroupVersionKind) {
+	err := wait.ExponentialBackoffWithContext(ctx, watchBackoff, func(context.Context) (bool, error) {
+		return c.watchKind(w, f, gvk)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.WarnContext(w.ctx, "Cannot watch the kind", "kind", gvk.String(), "error", err)
+		}
+		return
+	}
+	f.Start(ctx.Done())
+}
+
+func eventNamespace(r config.Resource) string {
+	if r.Namespace != "" {
+		return r.Namespace
+	}
+	return metav1.NamespaceDefault
+}
+
+func (w *Watcher) observe(obj interface{}, deleted bool) {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	w.update(u, deleted)
+}
+
+func (w *Watcher) observeEvent(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	e := &corev1.Event{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, e); err != nil {
+		return
+	}
+	w.event(e)
+}
+
+// update records the state of the resource and logs its condition
+// transitions.
+func (w *Watcher) update(u *unstructured.Unstructured, deleted bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	k := stateKey(u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName())
+	s, ok := w.states[k]
+	if !ok {
+		return
+	}
+	if deleted {
+		if s.Exists {
+			w.log(k, slog.LevelInfo, "Resource is deleted")
+			s.Exists, w.changed = false, true
+		}
+		return
+	}
+	if !s.Exists {
+		s.Exists, w.changed = true, true
+	}
+	conditions := conditionsOf(u)
+	for _, c := range conditions {
+		prev, found := s.Condition(c.Type)
+		if found && prev.Status == c.Status && prev.Reason == c.Reason {
+			continue
+		}
+		w.changed = true
+		args := []interface{}{"type", c.Type, "status", c.Status}
+		if found {
+			args = append(args, "previousStatus", prev.Status)
+		}
+		if c.Reason != "" {
+			args = append(args, "reason", c.Reason)
+		}
+		if c.Status != string(corev1.ConditionTrue) && c.Message != "" {
+			args = append(args, "message", c.Message)
+		}
+		w.log(k, slog.LevelInfo, "Condition changed", args...)
+	}
+	s.Conditions = conditions
+}
+
+// event records and logs the Warning event if it's about a tested resource
+// and happened after the watcher started.
+func (w *Watcher) event(e *corev1.Event) {
+	o := e.InvolvedObject
+	t := eventTime(e)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	k := stateKey(o.APIVersion, o.Kind, o.Namespace, o.Name)
+	s, ok := w.states[k]
+	if !ok || t.Before(w.started) || !t.After(s.LastEventTime) {
+		return
+	}
+	s.LastEvent = e.Reason + ": " + e.Message
+	s.LastEventTime = t
+	w.changed = true
+	w.log(k, slog.LevelWarn, "Warning event", "reason", e.Reason, "message", e.Message)
+}
+
+// log logs the message about the resource with the specified state key, and
+// records it in the history of the resource if recorded. It must be called
+// with the lock held.
+func (w *Watcher) log(k string, level slog.Level, msg string, args ...interface{}) {
+	w.logger.Log(w.ctx, level, msg, append([]interface{}{resourceAttr(w.states[k].Resource)}, args...)...)
+	if h, ok := w.history[k]; ok {
+		h.Log(w.ctx, level, msg, args...)
+	}
+}
+
+// summarize logs a summary of the resources every interval, if they changed
+// since the last summary.
+func (w *Watcher) summarize(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			w.logSummary()
+		}
+	}
+}
+
+func (w *Watcher) logSummary() {
+	w.mu.Lock()
+	changed := w.changed
+	w.changed = false
+	w.mu.Unlock()
+	if !changed {
+		return
+	}
+	for _, s := range w.Snapshot() {
+		w.logger.InfoContext(w.ctx, "Resource summary", resourceAttr(s.Resource), "state", s.summary())
+	}
+}
+
+// summary returns the state of the resource as a comma separated list of its
+// conditions.
+func (s ResourceState) summary() string {
+	if !s.Exists {
+		return "not found"
+	}
+	conditions := make([]string, 0, len(s.Conditions))
+	for _, c := range s.Conditions {
+		conditions = append(conditions, c.Type+"="+c.Status)
+	}
+	if len(conditions) == 0 {
+		return "no conditions"
+	}
+	return strings.Join(conditions, ", ")
+}
+
+func resourceAttr(r config.Resource) slog.Attr {
+	return logging.Resource(r.KindGroup, r.Namespace, r.Name)
+}
+
+func conditionsOf(u *unstructured.Unstructured) []Condition {
+	l, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	res := make([]Condition, 0, len(l))
+	for _, c := range l {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cond := Condition{}
+		cond.Type, _ = m["type"].(string)
+		cond.Status, _ = m["status"].(string)
+		cond.Reason, _ = m["reason"].(string)
+		cond.Message, _ = m["message"].(string)
+		res = append(res, cond)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Type < res[j].Type })
+	return res
+}
+
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+func stateKey(apiVersion, kind, namespace, name string) string {
+	return apiVersion + "/" + kind + "/" + namespace + "/" + name
+}
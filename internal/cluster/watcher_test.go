@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+var bucket = config.Resource{
+	Name:       "example",
+	KindGroup:  "bucket.s3.aws.upbound.io",
+	APIVersion: "s3.aws.upbound.io/v1beta1",
+	Kind:       "Bucket",
+}
+
+func testWatcher() (*Watcher, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	w := NewWatcher([]config.Resource{bucket})
+	w.logger = slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	return w, buf
+}
+
+func lines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func withConditions(name string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	u := object(bucket.APIVersion, bucket.Kind, "", name, "")
+	l := make([]interface{}, len(conditions))
+	for i, c := range conditions {
+		l[i] = c
+	}
+	_ = unstructured.SetNestedSlice(u.Object, l, "status", "conditions")
+	return u
+}
+
+func TestWatcherUpdate(t *testing.T) {
+	w, buf := testWatcher()
+	w.update(withConditions("example",
+		map[string]interface{}{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"},
+		map[string]interface{}{"type": "Ready", "status": "False", "reason": "Creating", "message": "creating the bucket"},
+	), false)
+	w.update(withConditions("example",
+		map[string]interface{}{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"},
+		map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available"},
+	), false)
+	w.update(withConditions("other",
+		map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available"},
+	), false)
+	w.update(withConditions("example"), true)
+
+	want := []string{
+		`level=INFO msg="Condition changed" resource=bucket.s3.aws.upbound.io/example type=Ready status=False reason=Creating message="creating the bucket"`,
+		`level=INFO msg="Condition changed" resource=bucket.s3.aws.upbound.io/example type=Synced status=True reason=ReconcileSuccess`,
+		`level=INFO msg="Condition changed" resource=bucket.s3.aws.upbound.io/example type=Ready status=True previousStatus=False reason=Available`,
+		`level=INFO msg="Resource is deleted" resource=bucket.s3.aws.upbound.io/example`,
+	}
+	if diff := cmp.Diff(want, lines(buf)); diff != "" {
+		t.Errorf("update(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWatcherEvent(t *testing.T) {
+	w, buf := testWatcher()
+	event := func(name string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			InvolvedObject: corev1.ObjectReference{APIVersion: bucket.APIVersion, Kind: bucket.Kind, Name: name},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "CannotObserveExternalResource",
+			Message:        "access denied",
+			LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+		}
+	}
+	w.event(event("example", time.Hour))
+	w.event(event("other", 0))
+	w.event(event("example", -time.Second))
+
+	want := []string{`level=WARN msg="Warning event" resource=bucket.s3.aws.upbound.io/example reason=CannotObserveExternalResource message="access denied"`}
+	if diff := cmp.Diff(want, lines(buf)); diff != "" {
+		t.Errorf("event(...): -want, +got:\n%s", diff)
+	}
+	if got := w.Snapshot()[0].LastEvent; got != "CannotObserveExternalResource: access denied" {
+		t.Errorf("event(...): want last event recorded, got %q", got)
+	}
+}
+
+func TestWatcherLogSummary(t *testing.T) {
+	w, buf := testWatcher()
+	w.logSummary()
+	w.update(withConditions("example",
+		map[string]interface{}{"type": "Ready", "status": "True"},
+		map[string]interface{}{"type": "Synced", "status": "True"},
+	), false)
+	buf.Reset()
+	w.logSummary()
+	w.logSummary()
+
+	want := []string{`level=INFO msg="Resource summary" resource=bucket.s3.aws.upbound.io/example state="Ready=True, Synced=True"`}
+	if diff := cmp.Diff(want, lines(buf)); diff != "" {
+		t.Errorf("logSummary(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWatcherRecordHistory(t *testing.T) {
+	other := config.Resource{Name: "other", Namespace: "default", KindGroup: "role.iam.aws.upbound.io", APIVersion: "iam.aws.upbound.io/v1beta1", Kind: "Role"}
+	w, _ := testWatcher()
+	w.states[stateKey(other.APIVersion, other.Kind, other.Namespace, other.Name)] = &ResourceState{Resource: other}
+	w.order = append(w.order, stateKey(other.APIVersion, other.Kind, other.Namespace, other.Name))
+	dir := t.TempDir()
+	if err := w.RecordHistory(dir); err != nil {
+		t.Fatalf("RecordHistory(...): %v", err)
+	}
+	w.update(withConditions("example",
+		map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available"},
+	), false)
+	w.update(withConditions("example"), true)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	timeField := regexp.MustCompile(`time=\S+ `)
+	want := map[string]string{
+		"bucket.s3.aws.upbound.io-example.log": `level=INFO msg="Condition changed" type=Ready status=True reason=Available` + "\n" +
+			`level=INFO msg="Resource is deleted"` + "\n",
+		"role.iam.aws.upbound.io-default-other.log": "",
+	}
+	got := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("cannot read the history directory: %v", err)
+	}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("cannot read the history file: %v", err)
+		}
+		got[e.Name()] = timeField.ReplaceAllString(string(b), "")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RecordHistory(...): -want, +got:\n%s", diff)
+	}
+}
+
+// lateMapper is a REST mapper whose mappings can be added while it's used.
+type lateMapper struct {
+	meta.RESTMapper
+	mu sync.Mutex
+}
+
+func (m *lateMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.RESTMapper.RESTMapping(gk, versions...)
+}
+
+func (m *lateMapper) add(gvk schema.GroupVersionKind) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RESTMapper.(*meta.DefaultRESTMapper).Add(gvk, meta.RESTScopeRoot)
+}
+
+func TestWatchUnmappedKind(t *testing.T) {
+	backoff := watchBackoff
+	watchBackoff = wait.Backoff{Duration: 10 * time.Millisecond, Factor: 1, Steps: math.MaxInt32}
+	t.Cleanup(func() { watchBackoff = backoff })
+
+	gvk := schema.FromAPIVersionAndKind(bucket.APIVersion, bucket.Kind)
+	mapper := &lateMapper{RESTMapper: meta.NewDefaultRESTMapper(nil)}
+	c := NewWithClient(fake.NewClientBuilder().WithRESTMapper(mapper).Build())
+	c.dynamic = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gvk.GroupVersion().WithResource("buckets"):       "BucketList",
+		corev1.SchemeGroupVersion.WithResource("events"): "EventList",
+	}, object(bucket.APIVersion, bucket.Kind, "", bucket.Name, ""))
+
+	w, _ := testWatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Watch(ctx, w, "", 0); err != nil {
+		t.Fatalf("Watch(...): %v", err)
+	}
+	mapper.add(gvk)
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return w.Snapshot()[0].Exists, nil
+	})
+	if err != nil {
+		t.Errorf("Watch(...): want the resource of the kind mapped after the watcher started to be observed: %v", err)
+	}
+}
@@ -93,6 +93,78 @@ func (b *Builder) SetOnlyCleanUptestResources(onlyCleanUptestResources bool) *Bu
 	return b
 }
 
+// SetWaitAllManaged sets whether the AutomatedTest should wait for all managed resources in the cluster to be deleted and returns the Builder.
+func (b *Builder) SetWaitAllManaged(waitAllManaged bool) *Builder {
+	b.test.WaitAllManaged = waitAllManaged
+	return b
+}
+
+// SetCleanupTimeout sets the timeout of waiting for the resources to be deleted at the end of the AutomatedTest and returns the Builder.
+func (b *Builder) SetCleanupTimeout(cleanupTimeout time.Duration) *Builder {
+	b.test.CleanupTimeout = cleanupTimeout
+	return b
+}
+
+// SetRunID sets the ID identifying the resources created by the AutomatedTest and returns the Builder.
+func (b *Builder) SetRunID(runID string) *Builder {
+	b.test.RunID = runID
+	return b
+}
+
+// SetArtifactsDir sets the directory the AutomatedTest should write the diagnostics bundles to and returns the Builder.
+func (b *Builder) SetArtifactsDir(artifactsDir string) *Builder {
+	b.test.ArtifactsDir = artifactsDir
+	return b
+}
+
+// SetProgress sets whether the AutomatedTest should show a live progress table on interactive terminals and returns the Builder.
+func (b *Builder) SetProgress(progress bool) *Builder {
+	b.test.Progress = progress
+	return b
+}
+
+// SetKeepArtifacts sets whether the AutomatedTest should keep the test directory even if the test succeeds and returns the Builder.
+func (b *Builder) SetKeepArtifacts(keepArtifacts bool) *Builder {
+	b.test.KeepArtifacts = keepArtifacts
+	return b
+}
+
+// SetResume sets whether the AutomatedTest should resume the failed run in its test directory and returns the Builder.
+func (b *Builder) SetResume(resume bool) *Builder {
+	b.test.Resume = resume
+	return b
+}
+
+// SetApplyTimeout sets the time budget of the apply step of the AutomatedTest and returns the Builder.
+func (b *Builder) SetApplyTimeout(timeout time.Duration) *Builder {
+	b.test.ApplyTimeout = timeout
+	return b
+}
+
+// SetUpdateTimeout sets the time budget of the update step of the AutomatedTest and returns the Builder.
+func (b *Builder) SetUpdateTimeout(timeout time.Duration) *Builder {
+	b.test.UpdateTimeout = timeout
+	return b
+}
+
+// SetImportTimeout sets the time budget of the import step of the AutomatedTest and returns the Builder.
+func (b *Builder) SetImportTimeout(timeout time.Duration) *Builder {
+	b.test.ImportTimeout = timeout
+	return b
+}
+
+// SetDeleteTimeout sets the time budget of the delete step of the AutomatedTest and returns the Builder.
+func (b *Builder) SetDeleteTimeout(timeout time.Duration) *Builder {
+	b.test.DeleteTimeout = timeout
+	return b
+}
+
+// SetNoCleanupOnFailure sets whether the AutomatedTest should skip the delete step when a phase fails and returns the Builder.
+func (b *Builder) SetNoCleanupOnFailure(noCleanupOnFailure bool) *Builder {
+	b.test.NoCleanupOnFailure = noCleanupOnFailure
+	return b
+}
+
 // SetRenderOnly sets whether the AutomatedTest should only render outputs without execution and returns the Builder.
 func (b *Builder) SetRenderOnly(renderOnly bool) *Builder {
 	b.test.RenderOnly = renderOnly
@@ -111,6 +183,36 @@ func (b *Builder) SetUseLibraryMode(useLibraryMode bool) *Builder {
 	return b
 }
 
+// SetImportMode sets the way the AutomatedTest should import the resources and returns the Builder.
+func (b *Builder) SetImportMode(importMode ImportMode) *Builder {
+	b.test.ImportMode = importMode
+	return b
+}
+
+// SetObserveOnlyTest sets whether the AutomatedTest should test the observe-only management policy and returns the Builder.
+func (b *Builder) SetObserveOnlyTest(observeOnlyTest bool) *Builder {
+	b.test.ObserveOnlyTest = observeOnlyTest
+	return b
+}
+
+// SetStabilityWindow sets the duration the AutomatedTest should watch the managed resources for changes after they become ready and returns the Builder.
+func (b *Builder) SetStabilityWindow(stabilityWindow time.Duration) *Builder {
+	b.test.StabilityWindow = stabilityWindow
+	return b
+}
+
+// SetSkipLeakCheck sets whether the AutomatedTest should skip checking for leaked external resources after the delete step and returns the Builder.
+func (b *Builder) SetSkipLeakCheck(skipLeakCheck bool) *Builder {
+	b.test.SkipLeakCheck = skipLeakCheck
+	return b
+}
+
+// SetOrderedApply sets whether the AutomatedTest should apply the resources tier by tier in dependency order and returns the Builder.
+func (b *Builder) SetOrderedApply(orderedApply bool) *Builder {
+	b.test.OrderedApply = orderedApply
+	return b
+}
+
 // Build finalizes and returns the constructed AutomatedTest instance.
 func (b *Builder) Build() *AutomatedTest {
 	return &b.test
@@ -14,6 +14,18 @@ import (
 const (
 	// AnnotationKeyTimeout defines a test time for the annotated resource.
 	AnnotationKeyTimeout = "uptest.upbound.io/timeout"
+	// AnnotationKeyTimeoutApply defines the time the annotated resource
+	// is given in the apply step, in seconds.
+	AnnotationKeyTimeoutApply = "uptest.upbound.io/timeout-apply"
+	// AnnotationKeyTimeoutUpdate defines the time the annotated resource
+	// is given in the update step, in seconds.
+	AnnotationKeyTimeoutUpdate = "uptest.upbound.io/timeout-update"
+	// AnnotationKeyTimeoutImport defines the time the annotated resource
+	// is given in the import step, in seconds.
+	AnnotationKeyTimeoutImport = "uptest.upbound.io/timeout-import"
+	// AnnotationKeyTimeoutDelete defines the time the annotated resource
+	// is given in the delete step, in seconds.
+	AnnotationKeyTimeoutDelete = "uptest.upbound.io/timeout-delete"
 	// AnnotationKeyConditions defines the list of status conditions to
 	// assert on the tested resource.
 	AnnotationKeyConditions = "uptest.upbound.io/conditions"
@@ -32,12 +44,89 @@ const (
 	// AnnotationKeyUpdateParameter defines the update parameter that will be
 	// used during the update step
 	AnnotationKeyUpdateParameter = "uptest.upbound.io/update-parameter"
+	// AnnotationKeyUpdatePatchType defines the type of the patch (merge,
+	// json or strategic) that is built from the update parameter.
+	AnnotationKeyUpdatePatchType = "uptest.upbound.io/update-patch-type"
+	// AnnotationKeyUpdatePath defines the dot separated path of the field
+	// that the update parameter is applied to. Defaults to spec.forProvider.
+	AnnotationKeyUpdatePath = "uptest.upbound.io/update-path"
+	// AnnotationKeyDriftHook defines the path to a drift hook script that
+	// changes the external resource of the tested resource out of band
+	// during the drift detection step.
+	AnnotationKeyDriftHook = "uptest.upbound.io/drift-hook"
+	// AnnotationKeyDriftParameter defines the value merged into the
+	// spec.forProvider of a twin resource with the Observe and Update
+	// management policies to change the external resource of the tested
+	// resource out of band during the drift detection step.
+	AnnotationKeyDriftParameter = "uptest.upbound.io/drift-parameter"
+	// AnnotationKeyVerifyOrphan determines whether the deletion of the
+	// tested resource with the Orphan deletion policy is verified during
+	// the delete step.
+	AnnotationKeyVerifyOrphan = "uptest.upbound.io/verify-orphan"
 	// AnnotationKeyExampleID is id of example that populated from example
 	// manifest. This information will be used for determining the root resource
 	AnnotationKeyExampleID = "meta.upbound.io/example-id"
 	// AnnotationKeyDisableImport determines whether the Import
 	// step of the resource to be tested will be executed or not.
 	AnnotationKeyDisableImport = "uptest.upbound.io/disable-import"
+	// AnnotationKeyDependsOn explicitly declares the dependencies of the
+	// annotated resource as a comma separated list of [kind.group/]name
+	// references, in addition to the ones resolved from its *Ref and
+	// *Selector fields.
+	AnnotationKeyDependsOn = "uptest.upbound.io/depends-on"
+	// AnnotationKeyCategory overrides the category of the annotated
+	// resource, which is otherwise determined from its API group, kind
+	// and spec. See Category for the possible values.
+	AnnotationKeyCategory = "uptest.upbound.io/category"
+	// LabelKeyRunID is the label that identifies the resources created by
+	// an uptest run, so that the run can clean up only its own resources.
+	LabelKeyRunID = "uptest.upbound.io/run-id"
+)
+
+// PatchType is the type of the patch applied to a resource during the
+// update step.
+type PatchType string
+
+const (
+	// PatchTypeMerge is a JSON merge patch (RFC 7386).
+	PatchTypeMerge PatchType = "merge"
+	// PatchTypeJSON is a JSON patch (RFC 6902).
+	PatchTypeJSON PatchType = "json"
+	// PatchTypeStrategic is a Kubernetes strategic merge patch.
+	PatchTypeStrategic PatchType = "strategic"
+)
+
+// ImportMode is the way the resources are imported during the import step.
+type ImportMode string
+
+const (
+	// ImportModeRestart pauses the resources, restarts the provider
+	// controllers and clears the status of the resources before they are
+	// reconciled again.
+	ImportModeRestart ImportMode = "restart"
+	// ImportModeFresh deletes the resources while orphaning the external
+	// resources and imports them into new objects with only the external
+	// name and the Observe management policy.
+	ImportModeFresh ImportMode = "fresh"
+)
+
+// Category is the category of a tested resource, which determines the test
+// steps applicable to the resource.
+type Category string
+
+const (
+	// CategoryManaged is a Crossplane managed resource.
+	CategoryManaged Category = "Managed"
+	// CategoryComposite is a Crossplane composite resource.
+	CategoryComposite Category = "Composite"
+	// CategoryClaim is a Crossplane claim.
+	CategoryClaim Category = "Claim"
+	// CategoryProviderConfig is a provider configuration, such as a
+	// ProviderConfig or a ClusterProviderConfig.
+	CategoryProviderConfig Category = "ProviderConfig"
+	// CategoryKubernetes is a plain Kubernetes object, such as a Secret, a
+	// ConfigMap or a Namespace, or a Crossplane package or composition.
+	CategoryKubernetes Category = "Kubernetes"
 )
 
 // AutomatedTest represents an automated test of resource example
@@ -59,11 +148,56 @@ type AutomatedTest struct {
 	SkipImport       bool
 	SkipWebhookCheck bool
 
+	ImportMode ImportMode
+
+	ObserveOnlyTest bool
+	StabilityWindow time.Duration
+	SkipLeakCheck   bool
+
 	OnlyCleanUptestResources bool
+	WaitAllManaged           bool
+	CleanupTimeout           time.Duration
+
+	// ApplyTimeout, UpdateTimeout, ImportTimeout and DeleteTimeout are the
+	// default time budgets of the apply, update, import and delete steps,
+	// which are independent of each other. The timeout of each resource is
+	// used if not set.
+	ApplyTimeout  time.Duration
+	UpdateTimeout time.Duration
+	ImportTimeout time.Duration
+	DeleteTimeout time.Duration
+
+	// NoCleanupOnFailure disables executing the delete step when a phase
+	// fails or the run is interrupted, so that the resources can be
+	// inspected.
+	NoCleanupOnFailure bool
+
+	// RunID identifies the resources created by the run. It's generated if
+	// not set.
+	RunID string
+
+	// ArtifactsDir is the directory the diagnostics bundles of the failed
+	// phases are written to. The test directory is used if not set.
+	ArtifactsDir string
+
+	// Progress shows a live progress table instead of the logs if stdout
+	// is an interactive terminal.
+	Progress bool
+
+	// KeepArtifacts keeps the test directory at the end of the run. It's
+	// always kept if the test fails.
+	KeepArtifacts bool
+
+	// Resume resumes the failed run whose test directory is kept, skipping
+	// the phases that passed. It implies NoCleanupOnFailure, so that the
+	// resources of a failed run are kept for the resumed run.
+	Resume bool
 
 	RenderOnly            bool
 	LogCollectionInterval time.Duration
 	UseLibraryMode        bool
+
+	OrderedApply bool
 }
 
 // Manifest represents a resource loaded from an example resource manifest file.
@@ -75,18 +209,46 @@ type Manifest struct {
 
 // TestCase represents a test-case to be run by chainsaw.
 type TestCase struct {
-	Timeout            time.Duration
+	Timeout time.Duration
+	// ApplyTimeout, UpdateTimeout, ImportTimeout and DeleteTimeout are the
+	// time budgets of the apply, update, import and delete steps. Timeout is
+	// used for the ones that are not set.
+	ApplyTimeout       time.Duration
+	UpdateTimeout      time.Duration
+	ImportTimeout      time.Duration
+	DeleteTimeout      time.Duration
 	SetupScriptPath    string
 	TeardownScriptPath string
 	SkipUpdate         bool
 	SkipImport         bool
 	SkipWebhookCheck   bool
 
+	ImportMode ImportMode
+
+	ObserveOnlyTest bool
+	DriftTest       bool
+
 	OnlyCleanUptestResources bool
+	WaitAllManaged           bool
+	CleanupTimeout           time.Duration
+
+	RunID string
+
+	OrderedApply bool
 
 	TestDirectory string
 }
 
+// SetDefaultPhaseTimeouts sets the time budgets of the steps that are not
+// set to the timeout of the test case.
+func (tc *TestCase) SetDefaultPhaseTimeouts() {
+	for _, d := range []*time.Duration{&tc.ApplyTimeout, &tc.UpdateTimeout, &tc.ImportTimeout, &tc.DeleteTimeout} {
+		if *d == 0 {
+			*d = tc.Timeout
+		}
+	}
+}
+
 // Resource represents a Kubernetes object to be tested and asserted
 // by uptest.
 type Resource struct {
@@ -96,6 +258,7 @@ type Resource struct {
 	YAML       string
 	APIVersion string
 	Kind       string
+	Category   Category
 
 	Timeout              time.Duration
 	Conditions           []string
@@ -104,11 +267,77 @@ type Resource struct {
 	PreDeleteScriptPath  string
 	PostDeleteScriptPath string
 
-	UpdateParameter   string
-	UpdateAssertKey   string
-	UpdateAssertValue string
+	UpdateParameter  string
+	UpdatePatchType  PatchType
+	UpdatePatch      string
+	UpdateAssertions []UpdateAssertion
+
+	DriftScriptPath string
+	DriftPatch      string
+	DriftAssertions []DriftAssertion
 
-	SkipImport bool
+	SkipImport   bool
+	VerifyOrphan bool
 
 	Root bool
+
+	// Tier is the dependency tier of the resource. Resources in tier 0 do
+	// not depend on any other tested resource.
+	Tier int
+}
+
+// IsManaged reports whether the resource is a managed resource.
+// Only managed resources are annotated as test resources, updated and
+// imported.
+func (r Resource) IsManaged() bool {
+	return r.Category == CategoryManaged
+}
+
+// IsComposite reports whether the resource is a composite resource or a
+// claim, whose composed resources are asserted as well.
+func (r Resource) IsComposite() bool {
+	return r.Category == CategoryComposite || r.Category == CategoryClaim
+}
+
+// HasDrift reports whether the drift detection step is configured for the
+// resource.
+func (r Resource) HasDrift() bool {
+	return r.DriftScriptPath != "" || r.DriftPatch != ""
+}
+
+// HasConditions reports whether the status conditions of the resource
+// are asserted.
+func (r Resource) HasConditions() bool {
+	switch r.Category {
+	case CategoryManaged, CategoryComposite, CategoryClaim:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpdateAssertion represents a field of a resource to be asserted
+// after the update step.
+type UpdateAssertion struct {
+	// Path is the JSONPath of the asserted field, e.g. .status.atProvider.tags.key
+	Path string
+	// Value is the expected value of the field.
+	Value string
+	// Absent asserts that the field does not exist anymore.
+	Absent bool
+	// DesiredPath is the JSONPath of the desired value of the field, e.g.
+	// .spec.forProvider.tags, if the field is asserted to be equal to it
+	// instead of to Value.
+	DesiredPath string
+}
+
+// DriftAssertion asserts that a drifted field of a resource is reconciled
+// back to its desired value.
+type DriftAssertion struct {
+	// Path is the JSONPath of the drifted field in status.atProvider.
+	Path string
+	// DesiredPath is the JSONPath of the field in spec.forProvider.
+	DesiredPath string
+	// Value is the drifted value of the field.
+	Value string
 }
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	xplogging "github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane/v2/cmd/crank/beta/trace"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/uptest/v2/internal/cluster"
+	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+const (
+	// DiagnosticsBundlePrefix is the prefix of the file names of the
+	// diagnostics bundles.
+	DiagnosticsBundlePrefix = "uptest-diagnostics-"
+
+	// diagnosticsTimeout is the time the diagnostics of a failed phase are
+	// collected in.
+	diagnosticsTimeout = 2 * time.Minute
+)
+
+// bundle is the content of a diagnostics bundle, keyed by file path.
+type bundle map[string][]byte
+
+// add adds the file to the bundle, or the error as a .error file if the
+// content could not be collected, so that a failure to collect a file does
+// not prevent collecting the others.
+func (b bundle) add(path string, content []byte, err error) {
+	if err != nil {
+		b[path+".error"] = []byte(err.Error() + "\n")
+		return
+	}
+	b[path] = content
+}
+
+// collectDiagnostics collects the diagnostics of the failed phase into a
+// gzipped tarball in the artifacts directory, or in the test directory if
+// not set, and returns its path. The bundle contains the YAML, the events and
+// the trace of each tested resource, the logs of the provider pods since the
+// run started, the Providers and ProviderRevisions, and the rendered chainsaw
+// files.
+func (t *Tester) collectDiagnostics(ctx context.Context, phase string, resources []config.Resource, since time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+	defer cancel()
+
+	b := bundle{}
+	c, err := cluster.New()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create the control plane client")
+	}
+	for _, r := range resources {
+		name := r.KindGroup + "-" + r.Name
+		if r.Namespace != "" {
+			name = r.KindGroup + "-" + r.Namespace + "-" + r.Name
+		}
+		y, err := c.Resource(ctx, r)
+		b.add(filepath.Join("resources", name+".yaml"), y, err)
+		e, err := c.Events(ctx, r)
+		b.add(filepath.Join("events", name+".yaml"), e, err)
+		tr, err := traceYAML(ctx, r, t.options.UseLibraryMode)
+		b.add(filepath.Join("traces", name+".yaml"), tr, err)
+	}
+	p, err := c.Providers(ctx)
+	b.add("providers.yaml", p, err)
+	logs, err := c.ProviderLogs(ctx, since)
+	if err != nil {
+		b.add("logs", nil, err)
+	}
+	for k, l := range logs {
+		b.add(filepath.Join("logs", strings.ReplaceAll(k, "/", "-")+".log"), l.Logs, l.Err)
+	}
+	caseDir := filepath.Join(t.options.Directory, caseDirectory)
+	entries, err := os.ReadDir(caseDir)
+	if err != nil {
+		b.add("chainsaw", nil, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		f, err := os.ReadFile(filepath.Join(caseDir, e.Name()))
+		b.add(filepath.Join("chainsaw", e.Name()), f, err)
+	}
+
+	dir := t.options.ArtifactsDir
+	if dir == "" {
+		dir = t.options.Directory
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil { //nolint:gosec // directory permissions are not critical here
+		return "", errors.Wrapf(err, "cannot create the artifacts directory %s", dir)
+	}
+	base := DiagnosticsBundlePrefix + strings.TrimSuffix(phase, filepath.Ext(phase))
+	if t.options.RunID != "" {
+		base = DiagnosticsBundlePrefix + t.options.RunID + "-" + strings.TrimSuffix(phase, filepath.Ext(phase))
+	}
+	path := filepath.Join(dir, base+".tar.gz")
+	return path, errors.Wrapf(writeTarball(path, base, b), "cannot write the diagnostics bundle %s", path)
+}
+
+// traceYAML returns the crossplane beta trace output of the resource as
+// YAML. The trace command only supports JSON among the structured outputs,
+// so its JSON output is converted to YAML.
+func traceYAML(ctx context.Context, r config.Resource, libraryMode bool) ([]byte, error) {
+	var out []byte
+	if libraryMode {
+		buf := &bytes.Buffer{}
+		kongCtx := &kong.Context{Kong: kong.Must(&trace.Cmd{}, kong.Writers(buf, buf))}
+		traceCmd := trace.Cmd{
+			Resource:  r.KindGroup,
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Output:    "json",
+		}
+		if err := traceCmd.Run(kongCtx, xplogging.NewNopLogger()); err != nil {
+			return nil, errors.Wrapf(err, "cannot trace %s/%s", r.KindGroup, r.Name)
+		}
+		out = buf.Bytes()
+	} else {
+		args := fmt.Sprintf(`"${CROSSPLANE_CLI}" beta trace %s %s -o json`, r.KindGroup, r.Name)
+		if r.Namespace != "" {
+			args = fmt.Sprintf(`"${CROSSPLANE_CLI}" beta trace %s %s -n %s -o json`, r.KindGroup, r.Name, r.Namespace)
+		}
+		var err error
+		out, err = exec.CommandContext(ctx, "bash", "-c", args).Output() //nolint:gosec // Disabling gosec to allow dynamic shell command execution
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot trace %s/%s", r.KindGroup, r.Name)
+		}
+	}
+	y, err := yaml.JSONToYAML(out)
+	return y, errors.Wrapf(err, "cannot convert the trace of %s/%s to YAML", r.KindGroup, r.Name)
+}
+
+// writeTarball writes the files of the bundle into a gzipped tarball at the
+// specified path, under the specified base directory.
+func writeTarball(path, base string, b bundle) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // The write errors are returned by the writers below.
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	names := make([]string, 0, len(b))
+	for n := range b {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	for _, n := range names {
+		h := &tar.Header{
+			Name:    filepath.ToSlash(filepath.Join(base, n)),
+			Mode:    0o644,
+			Size:    int64(len(b[n])),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return errors.Wrapf(err, "cannot write the header of %s", n)
+		}
+		if _, err := tw.Write(b[n]); err != nil {
+			return errors.Wrapf(err, "cannot write %s", n)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "cannot close the tar writer")
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Wrap(err, "cannot close the gzip writer")
+	}
+	return f.Close()
+}
+
+// logDiagnostics collects the diagnostics of the failed phase and logs where
+// the bundle is. Failing to collect the diagnostics does not change the
+// result of the test, so the error is only logged. The diagnostics are not
+// collected if the run is interrupted.
+func (t *Tester) logDiagnostics(ctx context.Context, phase string, resources []config.Resource, since time.Time) {
+	if ctx.Err() != nil {
+		slog.InfoContext(ctx, "Not collecting the diagnostics of the interrupted phase", logging.KeyPhase, phase)
+		return
+	}
+	path, err := t.collectDiagnostics(ctx, phase, resources, since)
+	if err != nil {
+		slog.WarnContext(ctx, "Cannot collect the diagnostics", logging.KeyPhase, phase, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "Diagnostics are collected", logging.KeyPhase, phase, "path", path)
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteTarball(t *testing.T) {
+	b := bundle{}
+	b.add("resources/bucket.s3.aws.upbound.io-example.yaml", []byte("kind: Bucket\n"), nil)
+	b.add("providers.yaml", nil, errors.New("cannot list Providers"))
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := writeTarball(path, "uptest-diagnostics-00-apply", b); err != nil {
+		t.Fatalf("writeTarball(...): %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck // Read only file.
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[h.Name] = string(c)
+	}
+	want := map[string]string{
+		"uptest-diagnostics-00-apply/resources/bucket.s3.aws.upbound.io-example.yaml": "kind: Bucket\n",
+		"uptest-diagnostics-00-apply/providers.yaml.error":                            "cannot list Providers\n",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("writeTarball(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+// Package graph builds the dependency graph of the resources tested by
+// uptest.
+package graph
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+// ignoredRefs are the reference fields that do not point to a dependency of
+// the resource, but to the objects it creates or composes.
+var ignoredRefs = map[string]bool{
+	"writeConnectionSecretToRef": true,
+	"publishConnectionDetailsTo": true,
+	"resourceRef":                true,
+	"resourceRefs":               true,
+	"claimRef":                   true,
+}
+
+// KindGroup returns the lower case kind.group of the object, e.g.
+// bucket.s3.aws.upbound.io, or only the kind for the objects in the core
+// group, e.g. secret.
+func KindGroup(o *unstructured.Unstructured) string {
+	gvk := o.GroupVersionKind()
+	return strings.TrimSuffix(strings.ToLower(gvk.Kind+"."+gvk.Group), ".")
+}
+
+// Dependencies returns the indexes of the objects that the object at index i
+// depends on. The dependencies are resolved from the *Ref, *Refs and
+// *Selector fields in the spec of the object, which only match the objects
+// of the kind derived from the field name, and the depends-on annotation.
+func Dependencies(objs []*unstructured.Unstructured, i int) []int {
+	deps := map[int]bool{}
+	o := objs[i]
+	if spec, ok := o.Object["spec"].(map[string]interface{}); ok {
+		walk(spec, func(key string, v interface{}) {
+			switch {
+			case strings.HasSuffix(key, "Ref"):
+				addRef(objs, i, strings.TrimSuffix(key, "Ref"), v, deps)
+			case strings.HasSuffix(key, "Refs"):
+				if l, ok := v.([]interface{}); ok {
+					for _, r := range l {
+						addRef(objs, i, strings.TrimSuffix(key, "Refs"), r, deps)
+					}
+				}
+			case strings.HasSuffix(key, "Selector"):
+				addSelector(objs, i, strings.TrimSuffix(key, "Selector"), v, deps)
+			}
+		})
+	}
+	for _, d := range strings.Split(o.GetAnnotations()[config.AnnotationKeyDependsOn], ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		kg, name := "", d
+		if s := strings.SplitN(d, "/", 2); len(s) == 2 {
+			kg, name = strings.ToLower(s[0]), s[1]
+		}
+		for j, c := range objs {
+			if j != i && c.GetName() == name && (kg == "" || KindGroup(c) == kg) {
+				deps[j] = true
+			}
+		}
+	}
+
+	res := make([]int, 0, len(deps))
+	for j := range deps {
+		res = append(res, j)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// Tiers returns the dependency tier of each object. The objects in tier 0
+// do not depend on any other object and the objects in tier n depend on at
+// least one object in tier n-1. Objects should be created in increasing and
+// deleted in decreasing tier order. An error is returned if the dependencies
+// have a cycle, in which case the callers can fall back to a single tier.
+func Tiers(objs []*unstructured.Unstructured) ([]int, error) {
+	deps := make([][]int, len(objs))
+	for i := range objs {
+		deps[i] = Dependencies(objs, i)
+	}
+
+	tiers := make([]int, len(objs))
+	// state is 0 for unvisited, 1 for in progress and 2 for done objects.
+	state := make([]int, len(objs))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			return errors.Errorf("dependency cycle detected at %s/%s", KindGroup(objs[i]), objs[i].GetName())
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		for _, d := range deps[i] {
+			if err := visit(d); err != nil {
+				return err
+			}
+			if tiers[d]+1 > tiers[i] {
+				tiers[i] = tiers[d] + 1
+			}
+		}
+		state[i] = 2
+		return nil
+	}
+	for i := range objs {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return tiers, nil
+}
+
+func walk(m map[string]interface{}, fn func(key string, v interface{})) {
+	for k, v := range m {
+		if ignoredRefs[k] {
+			continue
+		}
+		fn(k, v)
+		switch v := v.(type) {
+		case map[string]interface{}:
+			walk(v, fn)
+		case []interface{}:
+			for _, e := range v {
+				if em, ok := e.(map[string]interface{}); ok {
+					walk(em, fn)
+				}
+			}
+		}
+	}
+}
+
+// identifierSuffixes are the suffixes of the reference fields that name the
+// referenced field instead of the referenced kind, e.g. vpcIdRef references
+// the id of a VPC.
+var identifierSuffixes = []string{"Ids", "Id", "IDs", "ID", "Arns", "Arn", "ARNs", "ARN", "Names", "Name"}
+
+// candidates returns the indexes of the objects other than the object at
+// index i that can be referenced by the reference field with the specified
+// name without its Ref, Refs or Selector suffix, e.g. vpcId for vpcIdRef. The
+// referenced kind is derived from the field name, or is the specified kind
+// if the reference has one. The objects whose kinds are equal to the
+// referenced kind are preferred to the ones the referenced kind ends with,
+// e.g. Key for kmsKeyIdRef. Objects named alike are common in examples, so
+// the objects of other kinds are never candidates.
+func candidates(objs []*unstructured.Unstructured, i int, field, kind string) []int {
+	names := []string{strings.ToLower(kind)}
+	if kind == "" {
+		names = []string{strings.ToLower(field)}
+		for _, s := range identifierSuffixes {
+			if t := strings.TrimSuffix(field, s); t != field && t != "" {
+				names = append(names, strings.ToLower(t))
+				break
+			}
+		}
+	}
+	var exact, suffix []int
+	for j, o := range objs {
+		if j == i {
+			continue
+		}
+		k := strings.ToLower(o.GetKind())
+		switch {
+		case slices.Contains(names, k):
+			exact = append(exact, j)
+		case kind == "" && slices.ContainsFunc(names, func(n string) bool { return strings.HasSuffix(n, k) }):
+			suffix = append(suffix, j)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return suffix
+}
+
+func addRef(objs []*unstructured.Unstructured, i int, field string, ref interface{}, deps map[int]bool) {
+	r, ok := ref.(map[string]interface{})
+	if !ok {
+		return
+	}
+	name, _ := r["name"].(string)
+	if name == "" {
+		return
+	}
+	ns, _ := r["namespace"].(string)
+	if ns == "" {
+		ns = objs[i].GetNamespace()
+	}
+	kind, _ := r["kind"].(string)
+	for _, j := range candidates(objs, i, field, kind) {
+		o := objs[j]
+		// Cluster scoped objects can be referenced from namespaced ones.
+		if o.GetName() == name && (o.GetNamespace() == "" || o.GetNamespace() == ns) {
+			deps[j] = true
+		}
+	}
+}
+
+func addSelector(objs []*unstructured.Unstructured, i int, field string, selector interface{}, deps map[int]bool) {
+	s, ok := selector.(map[string]interface{})
+	if !ok {
+		return
+	}
+	ml, ok := s["matchLabels"].(map[string]interface{})
+	if !ok || len(ml) == 0 {
+		return
+	}
+	for _, j := range candidates(objs, i, field, "") {
+		o := objs[j]
+		labels := o.GetLabels()
+		matched := true
+		for k, v := range ml {
+			if vs, ok := v.(string); !ok || labels[k] != vs {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			deps[j] = true
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	vpcManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+  labels:
+    testing.upbound.io/example-name: example
+spec:
+  forProvider:
+    region: us-west-1
+`
+
+	subnetManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: example-subnet
+spec:
+  forProvider:
+    region: us-west-1
+    vpcIdSelector:
+      matchLabels:
+        testing.upbound.io/example-name: example
+`
+
+	instanceManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Instance
+metadata:
+  name: example-instance
+spec:
+  forProvider:
+    region: us-west-1
+    subnetIdRef:
+      name: example-subnet
+  writeConnectionSecretToRef:
+    name: example
+    namespace: upbound-system
+`
+
+	annotatedManifest = `apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: example-bucket
+  annotations:
+    uptest.upbound.io/depends-on: instance.ec2.aws.upbound.io/example-instance
+spec:
+  forProvider:
+    region: us-west-1
+`
+
+	sameNameVPCManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+`
+
+	sameNameSecurityGroupManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: SecurityGroup
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+    vpcIdRef:
+      name: example
+`
+
+	sameNameSecurityGroupRuleManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: SecurityGroupRule
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+    securityGroupIdRef:
+      name: example
+    sourceSecurityGroupIdRef:
+      name: example
+`
+
+	kmsKeyManifest = `apiVersion: kms.aws.upbound.io/v1beta1
+kind: Key
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+`
+
+	encryptedBucketManifest = `apiVersion: s3.aws.upbound.io/v1beta1
+kind: BucketServerSideEncryptionConfiguration
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+    rule:
+    - applyServerSideEncryptionByDefault:
+      - kmsMasterKeyIdRef:
+          name: example
+`
+
+	cycleManifest = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+  labels:
+    testing.upbound.io/example-name: example
+  annotations:
+    uptest.upbound.io/depends-on: example-subnet
+spec:
+  forProvider:
+    region: us-west-1
+`
+)
+
+func TestTiers(t *testing.T) {
+	type want struct {
+		tiers []int
+		err   bool
+	}
+	tests := map[string]struct {
+		manifests []string
+		want      want
+	}{
+		"NoDependencies": {
+			manifests: []string{vpcManifest, annotatedManifest},
+			want: want{
+				tiers: []int{0, 0},
+			},
+		},
+		"RefsSelectorsAndAnnotation": {
+			manifests: []string{annotatedManifest, instanceManifest, subnetManifest, vpcManifest},
+			want: want{
+				tiers: []int{3, 2, 1, 0},
+			},
+		},
+		"SameNameDifferentKinds": {
+			manifests: []string{sameNameSecurityGroupRuleManifest, sameNameSecurityGroupManifest, sameNameVPCManifest},
+			want: want{
+				tiers: []int{2, 1, 0},
+			},
+		},
+		"KindSuffix": {
+			manifests: []string{encryptedBucketManifest, kmsKeyManifest},
+			want: want{
+				tiers: []int{1, 0},
+			},
+		},
+		"Cycle": {
+			manifests: []string{cycleManifest, subnetManifest},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			objs := make([]*unstructured.Unstructured, len(tc.manifests))
+			for i, m := range tc.manifests {
+				objs[i] = &unstructured.Unstructured{}
+				if err := yaml.Unmarshal([]byte(m), &objs[i].Object); err != nil {
+					t.Fatalf("cannot unmarshal manifest: %v", err)
+				}
+			}
+			tiers, err := Tiers(objs)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("Tiers(...): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.tiers, tiers); diff != "" {
+				t.Errorf("Tiers(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
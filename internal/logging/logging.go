@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+// Package logging configures the structured logger of uptest. The logger is
+// the default slog logger, and the fields that are common to the messages of
+// a run or a phase, e.g. the run ID, are carried in the context, so that
+// they are added to every message logged with it.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+const (
+	// KeyRunID is the key of the run ID field.
+	KeyRunID = "runID"
+	// KeyPhase is the key of the phase field.
+	KeyPhase = "phase"
+	// KeyResource is the key of the resource field.
+	KeyResource = "resource"
+)
+
+// Format is the format of the log messages.
+type Format string
+
+const (
+	// FormatText logs the messages as key=value pairs.
+	FormatText Format = "text"
+	// FormatJSON logs the messages as JSON objects.
+	FormatJSON Format = "json"
+)
+
+// out is the writer of the messages, which can be switched, e.g. while the
+// progress table is shown.
+var out = &switchWriter{w: os.Stderr}
+
+// Setup sets the default slog logger to log the messages at or above the
+// specified level in the specified format, with the fields carried in the
+// context.
+func Setup(level string, format Format) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return errors.Wrapf(err, "cannot parse the log level %q", level)
+	}
+	slog.SetDefault(slog.New(NewHandler(out, l, format)))
+	return nil
+}
+
+// NewHandler returns a slog handler writing the messages at or above the
+// specified level in the specified format, with the fields carried in the
+// context.
+func NewHandler(w io.Writer, level slog.Leveler, format Format) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == FormatJSON {
+		return contextHandler{slog.NewJSONHandler(w, opts)}
+	}
+	return contextHandler{slog.NewTextHandler(w, opts)}
+}
+
+// SetOutput sets the writer of the messages of the logger set up by Setup
+// and returns a function restoring the previous one.
+func SetOutput(w io.Writer) func() {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	prev := out.w
+	out.w = w
+	return func() {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		out.w = prev
+	}
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of the context carrying the specified fields in
+// addition to the ones carried by the context. A field replaces the one with
+// the same key carried by the context, e.g. the phase of a nested phase.
+func WithAttrs(ctx context.Context, args ...interface{}) context.Context {
+	r := slog.Record{}
+	r.Add(args...)
+	attrs := append([]slog.Attr(nil), attrsFrom(ctx)...)
+	r.Attrs(func(a slog.Attr) bool {
+		for i := range attrs {
+			if attrs[i].Key == a.Key {
+				attrs[i] = a
+				return true
+			}
+		}
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// Resource returns the resource field of the object with the specified
+// kind.group, namespace and name.
+func Resource(kindGroup, namespace, name string) slog.Attr {
+	return slog.String(KeyResource, strings.Join(nonEmpty(kindGroup, namespace, name), "/"))
+}
+
+func nonEmpty(s ...string) []string {
+	res := make([]string, 0, len(s))
+	for _, v := range s {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// contextHandler adds the fields carried in the context to the messages.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(attrsFrom(ctx)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// switchWriter is a writer whose underlying writer can be switched.
+type switchWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var timeField = regexp.MustCompile(`time=\S+ |"time":"[^"]+",`)
+
+func TestHandler(t *testing.T) {
+	type args struct {
+		level  slog.Level
+		format Format
+		log    func(l *slog.Logger, ctx context.Context)
+	}
+	cases := map[string]struct {
+		args args
+		want string
+	}{
+		"TextWithContextFields": {
+			args: args{
+				level:  slog.LevelInfo,
+				format: FormatText,
+				log: func(l *slog.Logger, ctx context.Context) {
+					ctx = WithAttrs(ctx, KeyRunID, "abc")
+					ctx = WithAttrs(ctx, KeyPhase, "00-apply.yaml")
+					l.InfoContext(ctx, "Condition changed", Resource("bucket.s3.aws.upbound.io", "", "example"))
+				},
+			},
+			want: `level=INFO msg="Condition changed" resource=bucket.s3.aws.upbound.io/example runID=abc phase=00-apply.yaml` + "\n",
+		},
+		"NestedPhase": {
+			args: args{
+				level:  slog.LevelInfo,
+				format: FormatText,
+				log: func(l *slog.Logger, ctx context.Context) {
+					ctx = WithAttrs(ctx, KeyRunID, "abc", KeyPhase, "00-apply.yaml")
+					l.InfoContext(WithAttrs(ctx, KeyPhase, "stability"), "Watching the managed resources for changes")
+				},
+			},
+			want: `level=INFO msg="Watching the managed resources for changes" runID=abc phase=stability` + "\n",
+		},
+		"JSON": {
+			args: args{
+				level:  slog.LevelInfo,
+				format: FormatJSON,
+				log: func(l *slog.Logger, ctx context.Context) {
+					l.InfoContext(WithAttrs(ctx, KeyRunID, "abc"), "Deleted object", Resource("namespace", "", "test"))
+				},
+			},
+			want: `{"level":"INFO","msg":"Deleted object","resource":"namespace/test","runID":"abc"}` + "\n",
+		},
+		"BelowLevel": {
+			args: args{
+				level:  slog.LevelWarn,
+				format: FormatText,
+				log: func(l *slog.Logger, ctx context.Context) {
+					l.InfoContext(ctx, "Skipped")
+					l.WarnContext(ctx, "Cannot watch the resources")
+				},
+			},
+			want: `level=WARN msg="Cannot watch the resources"` + "\n",
+		},
+		"NamespacedResource": {
+			args: args{
+				level:  slog.LevelInfo,
+				format: FormatText,
+				log: func(l *slog.Logger, ctx context.Context) {
+					l.InfoContext(ctx, "Restarted provider Deployment", Resource("deployment.apps", "crossplane-system", "provider-aws"))
+				},
+			},
+			want: `level=INFO msg="Restarted provider Deployment" resource=deployment.apps/crossplane-system/provider-aws` + "\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tc.args.log(slog.New(NewHandler(buf, tc.args.level, tc.args.format)), context.Background())
+			if diff := cmp.Diff(tc.want, timeField.ReplaceAllString(buf.String(), "")); diff != "" {
+				t.Errorf("Handle(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	restoreFirst := SetOutput(first)
+	defer restoreFirst()
+	restoreSecond := SetOutput(second)
+	_, _ = out.Write([]byte("a"))
+	restoreSecond()
+	_, _ = out.Write([]byte("b"))
+
+	if diff := cmp.Diff("b", first.String()); diff != "" {
+		t.Errorf("SetOutput(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("a", second.String()); diff != "" {
+		t.Errorf("SetOutput(...): -want, +got:\n%s", diff)
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -19,11 +19,14 @@ import (
 	"strings"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/graph"
+	"github.com/crossplane/uptest/v2/internal/logging"
 )
 
 var (
@@ -57,6 +60,14 @@ func WithTestDirectory(path string) PreparerOption {
 	}
 }
 
+// WithRunID is a functional option that sets the run ID the Preparer labels
+// the manifests with.
+func WithRunID(runID string) PreparerOption {
+	return func(p *Preparer) {
+		p.runID = runID
+	}
+}
+
 // NewPreparer creates a new Preparer instance with the provided test file paths and optional configurations.
 // It applies any provided PreparerOption functions to customize the Preparer.
 func NewPreparer(testFilePaths []string, opts ...PreparerOption) *Preparer {
@@ -76,13 +87,15 @@ type Preparer struct {
 	testFilePaths  []string // Paths to the test files.
 	dataSourcePath string   // Path to the data source file.
 	testDirectory  string   // Directory where tests will be executed.
+	runID          string   // ID of the run the manifests are labelled with.
 }
 
 // PrepareManifests prepares and processes manifests from test files.
 // It performs the following steps:
 // 1. Cleans and recreates the case directory.
 // 2. Injects variables into test files.
-// 3. Decodes, processes, and validates each manifest file, skipping any that require manual intervention.
+// 3. Decodes, processes, and validates each manifest file, skipping any that require manual intervention,
+// and labels each manifest with the run ID if set.
 // 4. Returns the processed manifests or an error if any step fails.
 //
 //nolint:gocyclo // This function is not complex, gocyclo threshold was reached due to the error handling.
@@ -113,9 +126,16 @@ func (p *Preparer) PrepareManifests() ([]config.Manifest, error) {
 			}
 			if u != nil {
 				if v, ok := u.GetAnnotations()["upjet.upbound.io/manual-intervention"]; ok {
-					log.Printf("Skipping %s with name %s since it requires the following manual intervention: %s\n", u.GroupVersionKind().String(), u.GetName(), v)
+					args := []interface{}{logging.Resource(graph.KindGroup(u), u.GetNamespace(), u.GetName()), "intervention", v}
+					if p.runID != "" {
+						args = append(args, logging.KeyRunID, p.runID)
+					}
+					slog.Info("Skipping the manifest since it requires manual intervention", args...)
 					continue
 				}
+				if p.runID != "" {
+					meta.AddLabels(u, map[string]string{config.LabelKeyRunID: p.runID})
+				}
 				y, err := yaml.Marshal(u)
 				if err != nil {
 					return nil, errors.Wrapf(err, "cannot marshal manifest for \"%s/%s\"", u.GetObjectKind(), u.GetName())
@@ -179,6 +199,16 @@ func (p *Preparer) injectValues(manifestData string, dataSourceMap map[string]st
 	return manifestData
 }
 
+// NewRunID returns a random ID for an uptest run, which is a valid label
+// value.
+func NewRunID() string {
+	s := make([]rune, 10)
+	for i := range s {
+		s[i] = charset[rand.Intn(len(charset))] //nolint:gosec // no need for crypto/rand here
+	}
+	return string(s)
+}
+
 func generateRFC1123SubdomainCompatibleString() string {
 	s := make([]rune, 8)
 	for i := range s {
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/crossplane/uptest/v2/internal/cluster"
+)
+
+const (
+	// progressInterval is the interval the progress table is redrawn at.
+	progressInterval = time.Second
+	// maxEventLength is the maximum length of the last event shown in the
+	// progress table.
+	maxEventLength = 60
+)
+
+// progress is the live progress table of the tested resources shown on
+// interactive terminals. It's redrawn in place with the states observed by
+// the watcher.
+type progress struct {
+	out     io.Writer
+	watcher *cluster.Watcher
+	// width returns the width of the terminal the rows of the table are
+	// clipped to, so that they do not wrap, or 0 if unknown.
+	width func() int
+
+	mu         sync.Mutex
+	phase      string
+	phaseStart time.Time
+	// lines is the number of lines of the last drawn table, which are
+	// overwritten by the next one.
+	lines int
+}
+
+func newProgress(out io.Writer, w *cluster.Watcher) *progress {
+	return &progress{out: out, watcher: w, width: func() int { return 0 }, phaseStart: time.Now()}
+}
+
+// setPhase sets the phase the resources are in. It's a no-op if the
+// progress is not shown.
+func (p *progress) setPhase(phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phase = phase
+	p.phaseStart = time.Now()
+}
+
+// run redraws the table periodically until the context is done, and then
+// draws it one last time.
+func (p *progress) run(ctx context.Context) {
+	t := time.NewTicker(progressInterval)
+	defer t.Stop()
+	for {
+		p.draw()
+		select {
+		case <-ctx.Done():
+			p.draw()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (p *progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	table := p.table(time.Now(), p.width())
+	buf := &bytes.Buffer{}
+	if p.lines > 0 {
+		// Move the cursor up to the first line of the last table and clear
+		// the screen from there.
+		fmt.Fprintf(buf, "\x1b[%dA\x1b[J", p.lines)
+	}
+	buf.WriteString(table)
+	p.lines = strings.Count(table, "\n")
+	_, _ = p.out.Write(buf.Bytes())
+}
+
+// table returns the progress table of the resources at the specified time,
+// with each row clipped to the specified width if it's positive.
+func (p *progress) table(now time.Time, width int) string {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	elapsed := now.Sub(p.phaseStart).Round(time.Second)
+	fmt.Fprintln(tw, "RESOURCE\tPHASE\tCONDITIONS\tELAPSED\tLAST EVENT")
+	for _, s := range p.watcher.Snapshot() {
+		name := s.Resource.KindGroup + "/" + s.Resource.Name
+		if s.Resource.Namespace != "" {
+			name = s.Resource.KindGroup + "/" + s.Resource.Namespace + "/" + s.Resource.Name
+		}
+		conditions := "-"
+		switch {
+		case !s.Exists:
+			conditions = "not found"
+		case len(s.Conditions) > 0:
+			l := make([]string, 0, len(s.Conditions))
+			for _, c := range s.Conditions {
+				l = append(l, c.Type+"="+c.Status)
+			}
+			conditions = strings.Join(l, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s/%s\t%s\n", cell(name), cell(p.phase), cell(conditions), elapsed, s.Resource.Timeout, lastEvent(s.LastEvent))
+	}
+	_ = tw.Flush()
+	if width <= 0 {
+		return buf.String()
+	}
+	rows := strings.SplitAfter(buf.String(), "\n")
+	for i, row := range rows {
+		if r := []rune(strings.TrimSuffix(row, "\n")); len(r) > width {
+			rows[i] = string(r[:width]) + "\n"
+		}
+	}
+	return strings.Join(rows, "")
+}
+
+// lastEvent returns the last event cell of the table, which is truncated to
+// maxEventLength characters.
+func lastEvent(event string) string {
+	event = cell(event)
+	if r := []rune(event); len(r) > maxEventLength {
+		event = string(r[:maxEventLength-3]) + "..."
+	}
+	if event == "" {
+		event = "-"
+	}
+	return event
+}
+
+// cell returns the text with its whitespace, including the newlines,
+// collapsed into single spaces, so that it fits in a single cell of the
+// table.
+func cell(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
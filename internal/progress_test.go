@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/uptest/v2/internal/cluster"
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestProgressTable(t *testing.T) {
+	w := cluster.NewWatcher([]config.Resource{
+		{Name: "example", KindGroup: "bucket.s3.aws.upbound.io", APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Timeout: 10 * time.Minute},
+		{Name: "creds", Namespace: "upbound-system", KindGroup: "secret", APIVersion: "v1", Kind: "Secret", Timeout: 20 * time.Minute},
+	})
+	p := newProgress(&bytes.Buffer{}, w)
+	p.setPhase("00-apply.yaml")
+
+	want := `RESOURCE                          PHASE          CONDITIONS  ELAPSED      LAST EVENT
+bucket.s3.aws.upbound.io/example  00-apply.yaml  not found   1m30s/10m0s  -
+secret/upbound-system/creds       00-apply.yaml  not found   1m30s/20m0s  -
+`
+	if diff := cmp.Diff(want, p.table(p.phaseStart.Add(90*time.Second), 0)); diff != "" {
+		t.Errorf("table(...): -want, +got:\n%s", diff)
+	}
+
+	want = `RESOURCE                          PHASE
+bucket.s3.aws.upbound.io/example  00-ap
+secret/upbound-system/creds       00-ap
+`
+	if diff := cmp.Diff(want, p.table(p.phaseStart.Add(90*time.Second), 39)); diff != "" {
+		t.Errorf("table(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestProgressLastEvent(t *testing.T) {
+	tests := map[string]struct {
+		event string
+		want  string
+	}{
+		"Empty": {
+			want: "-",
+		},
+		"Whitespace": {
+			event: "CannotCreateExternalResource: create failed:\n\tinvalid  region",
+			want:  "CannotCreateExternalResource: create failed: invalid region",
+		},
+		"MultiByteRunes": {
+			event: strings.Repeat("ü", 70),
+			want:  strings.Repeat("ü", 57) + "...",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, lastEvent(tc.event)); diff != "" {
+				t.Errorf("lastEvent(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProgressDraw(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := newProgress(out, cluster.NewWatcher([]config.Resource{{Name: "example", KindGroup: "bucket.s3.aws.upbound.io"}}))
+	p.draw()
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("draw(): the first table should not move the cursor, got %q", out.String())
+	}
+	out.Reset()
+	p.draw()
+	if !strings.HasPrefix(out.String(), "\x1b[2A\x1b[J") {
+		t.Errorf("draw(): the next table should overwrite the 2 lines of the last one, got %q", out.String())
+	}
+}
+
+func TestProgressSetPhaseNil(_ *testing.T) {
+	var p *progress
+	p.setPhase("00-apply.yaml")
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+// report is the summary of a test run.
+type report struct {
+	phases []phaseResult
+	tiers  []tierResult
+}
+
+// phaseResult is the result of executing a single test file.
+type phaseResult struct {
+	name     string
+	duration time.Duration
+	err      error
+	// details are additional lines printed under the phase.
+	details []string
+}
+
+// tierResult is the time it took to apply a dependency tier and for its
+// resources to become ready.
+type tierResult struct {
+	tier     int
+	duration time.Duration
+}
+
+func (r *report) addPhase(name string, d time.Duration, err error, details ...string) {
+	r.phases = append(r.phases, phaseResult{name: name, duration: d.Round(time.Second), err: err, details: details})
+}
+
+func (r *report) print(ctx context.Context) {
+	if len(r.phases) == 0 {
+		return
+	}
+	slog.InfoContext(ctx, "Test summary")
+	for _, p := range r.phases {
+		if p.err != nil {
+			slog.ErrorContext(ctx, "Phase failed", logging.KeyPhase, p.name, "duration", p.duration, "error", p.err)
+		} else {
+			slog.InfoContext(ctx, "Phase passed", logging.KeyPhase, p.name, "duration", p.duration)
+		}
+		for _, d := range p.details {
+			slog.InfoContext(ctx, "Phase detail", logging.KeyPhase, p.name, "detail", d)
+		}
+		if p.name == testFiles[0] {
+			for _, t := range r.tiers {
+				slog.InfoContext(ctx, "Tier applied and ready", logging.KeyPhase, p.name, "tier", t.tier, "duration", t.duration)
+			}
+		}
+	}
+}
+
+// readTierTimings reads the tier timings written by the apply step when the
+// resources are applied in order. The tiers that have not been asserted yet
+// are not included.
+func readTierTimings(path string) ([]tierResult, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "cannot open tier timings file %s", path)
+	}
+	defer f.Close() //nolint:errcheck // Read only file, closing errors are not relevant.
+
+	var res []tierResult
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		tier, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid tier in tier timings file %s", path)
+		}
+		seconds, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid duration in tier timings file %s", path)
+		}
+		res = append(res, tierResult{tier: tier, duration: time.Duration(seconds) * time.Second})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].tier < res[j].tier
+	})
+	return res, errors.Wrapf(sc.Err(), "cannot read tier timings file %s", path)
+}
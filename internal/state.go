@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// stateFile is the file under the test directory the state of the run is
+// persisted to, so that a failed run can be resumed. The resolved manifests
+// and the rendered files are persisted in the case directory.
+const stateFile = "state.json"
+
+// runState is the persisted state of a run.
+type runState struct {
+	RunID  string       `json:"runID"`
+	Phases []phaseState `json:"phases,omitempty"`
+}
+
+// phaseState is the persisted result of a phase.
+type phaseState struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// passed returns whether the phase passed.
+func (s *runState) passed(phase string) bool {
+	for _, p := range s.Phases {
+		if p.Name == phase {
+			return p.Passed
+		}
+	}
+	return false
+}
+
+// setPhase records the result of the phase, replacing its previous result.
+func (s *runState) setPhase(phase string, err error) {
+	p := phaseState{Name: phase, Passed: err == nil}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	for i := range s.Phases {
+		if s.Phases[i].Name == phase {
+			s.Phases[i] = p
+			return
+		}
+	}
+	s.Phases = append(s.Phases, p)
+}
+
+// readState reads the state of the run persisted in the test directory. It
+// returns nil if no state is persisted.
+func readState(dir string) (*runState, error) {
+	path := filepath.Join(dir, stateFile)
+	b, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read the run state %s", path)
+	}
+	s := &runState{}
+	return s, errors.Wrapf(json.Unmarshal(b, s), "cannot unmarshal the run state %s", path)
+}
+
+// writeState persists the state of the run in the test directory.
+func writeState(dir string, s *runState) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the run state")
+	}
+	path := filepath.Join(dir, stateFile)
+	return errors.Wrapf(os.WriteFile(filepath.Clean(path), append(b, '\n'), 0o600), "cannot write the run state %s", path)
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestState(t *testing.T) {
+	dir := t.TempDir()
+	got, err := readState(dir)
+	if err != nil {
+		t.Fatalf("readState(...): %v", err)
+	}
+	if got != nil {
+		t.Errorf("readState(...): want nil state without a persisted state, got %+v", got)
+	}
+
+	s := &runState{RunID: "abc"}
+	s.setPhase("00-apply.yaml", nil)
+	s.setPhase("02-import.yaml", errors.New("timed out"))
+	s.setPhase("02-import.yaml", errors.New("cannot restart the provider Deployments"))
+	if err := writeState(dir, s); err != nil {
+		t.Fatalf("writeState(...): %v", err)
+	}
+	got, err = readState(dir)
+	if err != nil {
+		t.Fatalf("readState(...): %v", err)
+	}
+	want := &runState{
+		RunID: "abc",
+		Phases: []phaseState{
+			{Name: "00-apply.yaml", Passed: true},
+			{Name: "02-import.yaml", Error: "cannot restart the provider Deployments"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("readState(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	tr := NewTester(nil, &config.AutomatedTest{})
+	tr.resumed = &runState{Phases: []phaseState{
+		{Name: "00-apply.yaml", Passed: true},
+		{Name: stabilityPhase, Passed: true},
+		{Name: "01-update.yaml"},
+		{Name: "02-import.yaml", Passed: true},
+	}}
+	var got []string
+	for _, p := range []string{"00-apply.yaml", stabilityPhase, "01-update.yaml", "02-import.yaml", "03-delete.yaml"} {
+		if tr.skip(context.Background(), p) {
+			got = append(got, p)
+		}
+	}
+	if diff := cmp.Diff([]string{"00-apply.yaml", stabilityPhase}, got); diff != "" {
+		t.Errorf("skip(...): -want, +got:\n%s", diff)
+	}
+}
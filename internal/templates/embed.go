@@ -11,6 +11,16 @@ import _ "embed"
 //go:embed 00-apply.yaml.tmpl
 var inputFileTemplate string
 
+// observeFileTemplate is the template for the observe-only file.
+//
+//go:embed 00-observe.yaml.tmpl
+var observeFileTemplate string
+
+// driftFileTemplate is the template for the drift detection file.
+//
+//go:embed 00-drift.yaml.tmpl
+var driftFileTemplate string
+
 // updateFileTemplate is the template for the update file.
 //
 //go:embed 01-update.yaml.tmpl
@@ -21,7 +31,36 @@ var updateFileTemplate string
 //go:embed 02-import.yaml.tmpl
 var importFileTemplate string
 
+// importFreshFileTemplate is the template for the import file when the
+// resources are imported into fresh objects.
+//
+//go:embed 02-import-fresh.yaml.tmpl
+var importFreshFileTemplate string
+
 // deleteFileTemplate is the template for the delete file.
 //
 //go:embed 03-delete.yaml.tmpl
 var deleteFileTemplate string
+
+// teardownFileTemplate is the template for the teardown file.
+//
+//go:embed 04-teardown.yaml.tmpl
+var teardownFileTemplate string
+
+// composedTemplate contains the shared definitions for testing the
+// resources composed by composite resources and claims.
+//
+//go:embed _composed.tmpl
+var composedTemplate string
+
+// observeTemplate contains the shared definitions for creating Observe-only
+// managed resources for the external resources of the tested resources.
+//
+//go:embed _observe.tmpl
+var observeTemplate string
+
+// driftTemplate contains the shared definitions for detecting the drift
+// correction of the tested resources.
+//
+//go:embed _drift.tmpl
+var driftTemplate string
@@ -7,6 +7,8 @@
 package templates
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -16,25 +18,103 @@ import (
 )
 
 var fileTemplates = map[string]string{
-	"00-apply.yaml":  inputFileTemplate,
-	"01-update.yaml": updateFileTemplate,
-	"02-import.yaml": importFileTemplate,
-	"03-delete.yaml": deleteFileTemplate,
+	"00-apply.yaml":    inputFileTemplate,
+	"00-observe.yaml":  observeFileTemplate,
+	"00-drift.yaml":    driftFileTemplate,
+	"01-update.yaml":   updateFileTemplate,
+	"02-import.yaml":   importFileTemplate,
+	"03-delete.yaml":   deleteFileTemplate,
+	"04-teardown.yaml": teardownFileTemplate,
+}
+
+// helperTemplates contain the definitions shared by the file templates.
+var helperTemplates = []string{
+	composedTemplate,
+	observeTemplate,
+	driftTemplate,
+}
+
+// TierTimingsFile is the file in the test case directory that the apply and
+// assert durations of the dependency tiers are written to, when the
+// resources are applied in order. Each line of the file has the tier and its
+// duration in seconds.
+const TierTimingsFile = "tier-timings.log"
+
+// Tier is a group of resources in the same dependency tier.
+type Tier struct {
+	Tier      int
+	Name      string
+	Resources []config.Resource
+	// File is the input file containing the resources of the tier.
+	File string
+}
+
+// HasComposites reports whether the tier has any composite resources or
+// claims.
+func (t Tier) HasComposites() bool {
+	for _, r := range t.Resources {
+		if r.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOrphans reports whether the tier has any managed resources whose
+// deletion with the Orphan deletion policy is verified.
+func (t Tier) HasOrphans() bool {
+	for _, r := range t.Resources {
+		if r.IsManaged() && r.VerifyOrphan {
+			return true
+		}
+	}
+	return false
+}
+
+// TierInputFile returns the name of the input file containing the resources
+// of the specified dependency tier.
+func TierInputFile(tier int) string {
+	return fmt.Sprintf("test-input-tier-%d.yaml", tier)
+}
+
+// shellQuote quotes the string as a single word in a shell script, so that
+// no character of it is interpreted by the shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
 }
 
 // Render renders the specified list of resources as a test case
 // with the specified configuration.
 func Render(tc *config.TestCase, resources []config.Resource, skipDelete bool) (map[string]string, error) {
 	data := struct {
-		Resources []config.Resource
-		TestCase  config.TestCase
+		Resources       []config.Resource
+		TestCase        config.TestCase
+		ApplyTiers      []Tier
+		DeleteTiers     []Tier
+		ProviderConfigs []config.Resource
+		Secrets         []config.Resource
+		TierTimingsFile string
 	}{
-		Resources: resources,
-		TestCase:  *tc,
+		Resources:       resources,
+		TestCase:        *tc,
+		ApplyTiers:      applyTiers(tc, resources),
+		DeleteTiers:     deleteTiers(resources),
+		ProviderConfigs: providerConfigs(resources),
+		Secrets:         secrets(resources),
+		TierTimingsFile: TierTimingsFile,
 	}
+	data.TestCase.SetDefaultPhaseTimeouts()
 
 	res := make(map[string]string, len(fileTemplates))
 	for name, tmpl := range fileTemplates {
+		// Skip the observe-only template unless the observe-only test is enabled
+		if !tc.ObserveOnlyTest && name == "00-observe.yaml" {
+			continue
+		}
+		// Skip the drift template unless any resource is configured for it
+		if !tc.DriftTest && name == "00-drift.yaml" {
+			continue
+		}
 		// Skip templates with names starting with "01-" if skipUpdate is true
 		if tc.SkipUpdate && strings.HasPrefix(name, "01-") {
 			continue
@@ -43,13 +123,26 @@ func Render(tc *config.TestCase, resources []config.Resource, skipDelete bool) (
 		if tc.SkipImport && strings.HasPrefix(name, "02-") {
 			continue
 		}
-		// Skip templates with names starting with "03-" if skipDelete is true
-		if skipDelete && strings.HasPrefix(name, "03-") {
+		// Skip templates with names starting with "03-" or "04-" if skipDelete is true
+		if skipDelete && (strings.HasPrefix(name, "03-") || strings.HasPrefix(name, "04-")) {
 			continue
 		}
+		// Skip the teardown template unless there is anything to tear down
+		if name == "04-teardown.yaml" && len(data.ProviderConfigs) == 0 && len(data.Secrets) == 0 && tc.TeardownScriptPath == "" {
+			continue
+		}
+
+		if name == "02-import.yaml" && tc.ImportMode == config.ImportModeFresh {
+			tmpl = importFreshFileTemplate
+		}
 
-		t, err := template.New(name).Parse(tmpl)
-		if err != nil {
+		t := template.New(name).Funcs(template.FuncMap{"shellQuote": shellQuote})
+		for _, h := range helperTemplates {
+			if _, err := t.Parse(h); err != nil {
+				return nil, errors.Wrap(err, "cannot parse the shared template definitions")
+			}
+		}
+		if _, err := t.Parse(tmpl); err != nil {
 			return nil, errors.Wrapf(err, "cannot parse template %q", name)
 		}
 
@@ -62,3 +155,99 @@ func Render(tc *config.TestCase, resources []config.Resource, skipDelete bool) (
 
 	return res, nil
 }
+
+// ApplyTiers groups the resources by their dependency tiers in increasing
+// order, so that the dependencies of a resource are applied before the
+// resource itself.
+func ApplyTiers(resources []config.Resource) []Tier {
+	byTier := map[int][]config.Resource{}
+	for _, r := range resources {
+		byTier[r.Tier] = append(byTier[r.Tier], r)
+	}
+	tiers := make([]Tier, 0, len(byTier))
+	for t, rs := range byTier {
+		tiers = append(tiers, Tier{Tier: t, Name: tierName(t), Resources: rs, File: TierInputFile(t)})
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Tier < tiers[j].Tier
+	})
+	return tiers
+}
+
+// applyTiers returns the tiers the resources are applied in. Unless the
+// resources are applied in order, all resources are in a single tier.
+func applyTiers(tc *config.TestCase, resources []config.Resource) []Tier {
+	if !tc.OrderedApply {
+		return []Tier{{Resources: resources, File: tc.TestDirectory}}
+	}
+	return ApplyTiers(resources)
+}
+
+// deleteTiers groups the resources to be deleted by their dependency tiers
+// in reverse order, so that the dependents of a resource are deleted before
+// the resource itself. Plain Kubernetes objects are deleted in a separate tier
+// after all other resources are gone. Provider configs and Secrets, which
+// hold the provider credentials that the managed resources and the leak check
+// still need, are deleted by the teardown step instead. There is always at
+// least one tier.
+func deleteTiers(resources []config.Resource) []Tier {
+	byTier := map[int][]config.Resource{}
+	var objects []config.Resource
+	for _, r := range resources {
+		switch {
+		case r.Category == config.CategoryProviderConfig || isSecret(r):
+			continue
+		case r.Category == config.CategoryKubernetes:
+			objects = append(objects, r)
+			continue
+		}
+		byTier[r.Tier] = append(byTier[r.Tier], r)
+	}
+	tiers := make([]Tier, 0, len(byTier)+1)
+	for t, rs := range byTier {
+		tiers = append(tiers, Tier{Tier: t, Name: tierName(t), Resources: rs})
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Tier > tiers[j].Tier
+	})
+	if len(objects) > 0 {
+		tiers = append(tiers, Tier{Name: "Kubernetes Objects", Resources: objects})
+	}
+	if len(tiers) == 0 {
+		tiers = append(tiers, Tier{})
+	}
+	return tiers
+}
+
+// providerConfigs returns the provider configs, which are deleted by the
+// teardown step after the managed resources are deleted and checked for
+// leaks.
+func providerConfigs(resources []config.Resource) []config.Resource {
+	var pcs []config.Resource
+	for _, r := range resources {
+		if r.Category == config.CategoryProviderConfig {
+			pcs = append(pcs, r)
+		}
+	}
+	return pcs
+}
+
+// secrets returns the Secrets, which are deleted by the teardown step after
+// the provider configs that may reference them.
+func secrets(resources []config.Resource) []config.Resource {
+	var ss []config.Resource
+	for _, r := range resources {
+		if isSecret(r) {
+			ss = append(ss, r)
+		}
+	}
+	return ss
+}
+
+func isSecret(r config.Resource) bool {
+	return r.Category == config.CategoryKubernetes && r.KindGroup == "secret"
+}
+
+func tierName(tier int) string {
+	return fmt.Sprintf("Tier %d", tier)
+}
@@ -5,6 +5,7 @@
 package templates
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -65,6 +66,8 @@ func TestRender(t *testing.T) {
 					SetupScriptPath: "/tmp/setup.sh",
 					Timeout:         10 * time.Minute,
 					TestDirectory:   "/tmp/test-input.yaml",
+					RunID:           "abcde12345",
+					CleanupTimeout:  10 * time.Minute,
 				},
 				resources: []config.Resource{
 					{
@@ -74,6 +77,7 @@ func TestRender(t *testing.T) {
 						KindGroup:  "s3.aws.upbound.io",
 						YAML:       bucketManifest,
 						Conditions: []string{"Test"},
+						Category:   config.CategoryManaged,
 					},
 				},
 			},
@@ -185,52 +189,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/check_endpoints.sh -o /tmp/check_endpoints.sh && chmod +x /tmp/check_endpoints.sh
@@ -330,8 +295,9 @@ spec:
         content: |
           ${KUBECTL} wait --for=delete s3.aws.upbound.io/example-bucket --timeout 10m0s
     - script:
+        timeout: 10m0s
         content: |
-          ${KUBECTL} wait managed --all --for=delete --timeout -1s
+          ${KUBECTL} wait managed --all-namespaces --selector uptest.upbound.io/run-id=abcde12345 --for=delete --timeout 10m0s
 `,
 				},
 			},
@@ -350,6 +316,7 @@ spec:
 						KindGroup:  "s3.aws.upbound.io",
 						YAML:       bucketManifest,
 						Conditions: []string{"Test"},
+						Category:   config.CategoryManaged,
 					},
 				},
 			},
@@ -456,52 +423,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/check_endpoints.sh -o /tmp/check_endpoints.sh && chmod +x /tmp/check_endpoints.sh
@@ -600,9 +528,6 @@ spec:
     - script:
         content: |
           ${KUBECTL} wait --for=delete s3.aws.upbound.io/example-bucket --timeout 10m0s
-    - script:
-        content: |
-          ${KUBECTL} wait managed --all --for=delete --timeout -1s
 `,
 				},
 			},
@@ -614,6 +539,8 @@ spec:
 					SetupScriptPath:    "/tmp/setup.sh",
 					TeardownScriptPath: "/tmp/teardown.sh",
 					TestDirectory:      "/tmp/test-input.yaml",
+					WaitAllManaged:     true,
+					CleanupTimeout:     10 * time.Minute,
 				},
 				resources: []config.Resource{
 					{
@@ -625,6 +552,7 @@ spec:
 						PreAssertScriptPath:  "/tmp/bucket/pre-assert.sh",
 						PostDeleteScriptPath: "/tmp/bucket/post-delete.sh",
 						Conditions:           []string{"Test"},
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:                 claimManifest,
@@ -637,12 +565,14 @@ spec:
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
 						SkipImport:           true,
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:      secretManifest,
 						Name:      "test-secret",
-						KindGroup: "secret.",
+						KindGroup: "secret",
 						Namespace: "upbound-system",
+						Category:  config.CategoryKubernetes,
 					},
 				},
 			},
@@ -771,53 +701,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          retry_kubectl "${KUBECTL} annotate --namespace upbound-system  cluster.gcp.platformref.upbound.io/test-cluster-claim crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/check_endpoints.sh -o /tmp/check_endpoints.sh && chmod +x /tmp/check_endpoints.sh
@@ -924,10 +814,34 @@ spec:
         content: |
           ${KUBECTL} wait --namespace upbound-system --for=delete cluster.gcp.platformref.upbound.io/test-cluster-claim --timeout 10m0s
     - script:
+        timeout: 10m0s
         content: |
-          ${KUBECTL} wait managed --all --for=delete --timeout -1s
+          ${KUBECTL} wait managed --all --for=delete --timeout 10m0s
+`,
+					"04-teardown.yaml": `# This file belongs to the teardown step, which is executed after the delete
+# step and the leak check.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: teardown
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Provider Configs and Secrets
+    description: |
+      Delete the provider configs and then the Secrets, which are kept until
+      the managed resources are deleted and their external resources are
+      checked for leaks, as both need the credentials of the provider configs.
+    # The cleanup operations of a step are run at the end of the test, even
+    # if the step fails, so the teardown script always runs.
+    cleanup:
     - command:
         entrypoint: /tmp/teardown.sh
+    try:
+    - script:
+        content: |
+          ${KUBECTL} delete --namespace upbound-system secret/test-secret --ignore-not-found --timeout 10m0s
 `,
 				},
 			},
@@ -974,6 +888,7 @@ func TestRenderWithSkipDelete(t *testing.T) {
 						KindGroup:  "s3.aws.upbound.io",
 						YAML:       bucketManifest,
 						Conditions: []string{"Test"},
+						Category:   config.CategoryManaged,
 					},
 				},
 			},
@@ -1085,52 +1000,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/check_endpoints.sh -o /tmp/check_endpoints.sh && chmod +x /tmp/check_endpoints.sh
@@ -1208,6 +1084,7 @@ spec:
 						PostDeleteScriptPath: "/tmp/bucket/post-delete.sh",
 						SkipImport:           true,
 						Conditions:           []string{"Test"},
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:                 claimManifest,
@@ -1220,12 +1097,14 @@ spec:
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
 						SkipImport:           true,
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:      secretManifest,
 						Name:      "test-secret",
-						KindGroup: "secret.",
+						KindGroup: "secret",
 						Namespace: "upbound-system",
+						Category:  config.CategoryKubernetes,
 					},
 				},
 			},
@@ -1362,6 +1241,7 @@ spec:
 						PreAssertScriptPath:  "/tmp/bucket/pre-assert.sh",
 						PostDeleteScriptPath: "/tmp/bucket/post-delete.sh",
 						Conditions:           []string{"Test"},
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:                 claimManifest,
@@ -1374,12 +1254,14 @@ spec:
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
 						SkipImport:           true,
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:      secretManifest,
 						Name:      "test-secret",
-						KindGroup: "secret.",
+						KindGroup: "secret",
 						Namespace: "upbound-system",
+						Category:  config.CategoryKubernetes,
 					},
 				},
 			},
@@ -1508,53 +1390,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          retry_kubectl "${KUBECTL} annotate --namespace upbound-system  cluster.gcp.platformref.upbound.io/test-cluster-claim crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/check_endpoints.sh -o /tmp/check_endpoints.sh && chmod +x /tmp/check_endpoints.sh
@@ -1634,6 +1476,7 @@ spec:
 						PostAssertScriptPath: "/tmp/claim/post-assert.sh",
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
+						Category:             config.CategoryManaged,
 					},
 				},
 			},
@@ -1740,6 +1583,7 @@ spec:
 						PostAssertScriptPath: "/tmp/claim/post-assert.sh",
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
+						Category:             config.CategoryManaged,
 					},
 					{
 						YAML:                 claimManifest,
@@ -1750,6 +1594,7 @@ spec:
 						PostAssertScriptPath: "/tmp/claim/post-assert.sh",
 						PreDeleteScriptPath:  "/tmp/claim/pre-delete.sh",
 						Conditions:           []string{"Ready", "Synced"},
+						Category:             config.CategoryManaged,
 					},
 				},
 			},
@@ -1894,6 +1739,7 @@ func TestRenderWithSkipWebhookCheck(t *testing.T) {
 						KindGroup:  "s3.aws.upbound.io",
 						YAML:       bucketManifest,
 						Conditions: []string{"Test"},
+						Category:   config.CategoryManaged,
 					},
 				},
 			},
@@ -1983,6 +1829,7 @@ spec:
 						KindGroup:  "s3.aws.upbound.io",
 						YAML:       bucketManifest,
 						Conditions: []string{"Test"},
+						Category:   config.CategoryManaged,
 					},
 				},
 			},
@@ -2065,52 +1912,13 @@ spec:
   steps:
   - name: Remove State
     description: |
-      Removes the resource statuses from MRs and controllers. For controllers
-      the scale down&up was applied. For MRs status conditions are patched.
-      Also, for the assertion step, the ID before import was stored in the
-      uptest-old-id annotation.
+      Removes the resource statuses from MRs. The MRs were paused and the
+      controllers were restarted before this step. For MRs status conditions
+      are patched. Also, for the assertion step, the ID before import was
+      stored in the uptest-old-id annotation.
     try:
     - script:
         content: |
-          retry_kubectl() {
-            local max_attempts=10
-            local delay=5
-            local attempt=1
-            local cmd="$1"
-            while [ $attempt -le $max_attempts ]; do
-              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
-              if eval "$cmd"; then
-                echo "Kubectl operation successful on attempt $attempt"
-                return 0
-              else
-                echo "Kubectl operation failed on attempt $attempt"
-                if [ $attempt -lt $max_attempts ]; then
-                  echo "Retrying in ${delay}s..."
-                  sleep $delay
-                fi
-                ((attempt++))
-              fi
-            done
-            echo "Kubectl operation failed after $max_attempts attempts"
-            return 1
-          }
-          retry_kubectl "${KUBECTL} annotate  s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite"
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 0}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale down"
-          fi
-    - sleep:
-        duration: 10s
-    - script:
-        content: |
-          PROVIDER_CONFIGS=$(${KUBECTL} get deploymentruntimeconfig --no-headers -o custom-columns=":metadata.name" | grep "provider-" || true)
-          if [ -n "$PROVIDER_CONFIGS" ]; then
-            echo "$PROVIDER_CONFIGS" | xargs ${KUBECTL} patch deploymentruntimeconfig --type='json' -p='[{"op": "replace", "path": "/spec/deploymentTemplate/spec/replicas", "value": 1}]'
-          else
-            echo "No provider DeploymentRuntimeConfigs found to scale up"
-          fi
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch.sh -o /tmp/patch.sh && chmod +x /tmp/patch.sh
           curl -sL https://raw.githubusercontent.com/crossplane/uptest/main/hack/patch-ns.sh -o /tmp/patch-ns.sh && chmod +x /tmp/patch-ns.sh
           /tmp/patch.sh s3.aws.upbound.io example-bucket
@@ -2178,3 +1986,1531 @@ spec:
 		})
 	}
 }
+
+func TestRenderUpdate(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"JSONPatch": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:       10 * time.Minute,
+					TestDirectory: "/tmp/test-input.yaml",
+				},
+				resources: []config.Resource{
+					{
+						Name:            "example-bucket",
+						APIVersion:      "bucket.s3.aws.upbound.io/v1alpha1",
+						Kind:            "Bucket",
+						KindGroup:       "s3.aws.upbound.io",
+						YAML:            bucketManifest,
+						Conditions:      []string{"Test"},
+						UpdatePatchType: config.PatchTypeJSON,
+						UpdatePatch:     `[{"op":"remove","path":"/spec/forProvider/tags/1"},{"op":"replace","path":"/spec/forProvider/description","value":"it's updated"}]`,
+						UpdateAssertions: []config.UpdateAssertion{
+							{Path: ".status.atProvider.tags", DesiredPath: ".spec.forProvider.tags"},
+							{Path: ".status.atProvider.description", Value: "it's updated"},
+							{Path: ".spec.managementPolicies", Value: `["Observe","Update"]`},
+							{Path: ".status.atProvider.tags.removed", Absent: true},
+						},
+						Root:     true,
+						Category: config.CategoryManaged,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource update step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: update
+spec:
+  timeouts:
+    apply: 10m0s
+    assert: 10m0s
+    exec: 10m0s
+  steps:
+  - name: Update Root Resource
+    description: |
+      Update the root resource by using the specified update-parameter in annotation.
+      Before updating the resources, the status conditions are cleaned.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} --subresource=status patch s3.aws.upbound.io/example-bucket --type=merge -p '{\"status\":{\"conditions\":[]}}'"
+          patch='[{"op":"remove","path":"/spec/forProvider/tags/1"},{"op":"replace","path":"/spec/forProvider/description","value":"it'"'"'s updated"}]'
+          retry_kubectl "${KUBECTL} patch s3.aws.upbound.io/example-bucket --type=json -p \"\$patch\""
+  - name: Assert Updated Resource
+    description: |
+      Assert update operation. Firstly check the status conditions. Then assert
+      the updated field in status.atProvider.
+    try:
+    - assert:
+        resource:
+          apiVersion: bucket.s3.aws.upbound.io/v1alpha1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          status:
+            ((conditions[?type == 'Test'])[0]):
+              status: "True"
+    - script:
+        content: |
+          [ "$(${KUBECTL} get s3.aws.upbound.io/example-bucket -o=jsonpath='{.status.atProvider.tags}')" = "$(${KUBECTL} get s3.aws.upbound.io/example-bucket -o=jsonpath='{.spec.forProvider.tags}')" ]
+    - script:
+        content: |
+          ${KUBECTL} get s3.aws.upbound.io/example-bucket -o=jsonpath='{.status.atProvider.description}' | grep -Fxq -- 'it'"'"'s updated'
+    - script:
+        content: |
+          ${KUBECTL} get s3.aws.upbound.io/example-bucket -o=jsonpath='{.spec.managementPolicies}' | grep -Fxq -- '["Observe","Update"]'
+    - script:
+        content: test -z "$(${KUBECTL} get  s3.aws.upbound.io/example-bucket -o=jsonpath='{.status.atProvider.tags.removed}' 2>/dev/null)"
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, true)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["01-update.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderDeleteTiers(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"ReverseDependencyOrder": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:                  10 * time.Minute,
+					TestDirectory:            "/tmp/test-input.yaml",
+					OnlyCleanUptestResources: true,
+				},
+				resources: []config.Resource{
+					{
+						Name:      "example-vpc",
+						KindGroup: "vpc.ec2.aws.upbound.io",
+						Category:  config.CategoryManaged,
+					},
+					{
+						Name:      "example-subnet",
+						KindGroup: "subnet.ec2.aws.upbound.io",
+						Tier:      1,
+						Category:  config.CategoryManaged,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource delete step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: delete
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Resources (Tier 1)
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} delete subnet.ec2.aws.upbound.io/example-subnet --wait=false --ignore-not-found"
+  - name: Assert Deletion (Tier 1)
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --for=delete subnet.ec2.aws.upbound.io/example-subnet --timeout 10m0s
+  - name: Delete Resources (Tier 0)
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} delete vpc.ec2.aws.upbound.io/example-vpc --wait=false --ignore-not-found"
+  - name: Assert Deletion (Tier 0)
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --for=delete vpc.ec2.aws.upbound.io/example-vpc --timeout 10m0s
+`,
+			},
+		},
+		"KubernetesObjectsLast": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:                  10 * time.Minute,
+					TestDirectory:            "/tmp/test-input.yaml",
+					OnlyCleanUptestResources: true,
+				},
+				resources: []config.Resource{
+					{
+						Name:      "example-config",
+						Namespace: "default",
+						KindGroup: "configmap",
+						Category:  config.CategoryKubernetes,
+					},
+					{
+						Name:      "aws-creds",
+						Namespace: "crossplane-system",
+						KindGroup: "secret",
+						Category:  config.CategoryKubernetes,
+					},
+					{
+						Name:      "example-vpc",
+						KindGroup: "vpc.ec2.aws.upbound.io",
+						Category:  config.CategoryManaged,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource delete step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: delete
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Resources (Tier 0)
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} delete vpc.ec2.aws.upbound.io/example-vpc --wait=false --ignore-not-found"
+  - name: Assert Deletion (Tier 0)
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --for=delete vpc.ec2.aws.upbound.io/example-vpc --timeout 10m0s
+  - name: Delete Resources (Kubernetes Objects)
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} delete configmap/example-config --wait=false --namespace default --ignore-not-found"
+  - name: Assert Deletion (Kubernetes Objects)
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --namespace default --for=delete configmap/example-config --timeout 10m0s
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["03-delete.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderTeardown(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out      string
+		rendered bool
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"ProviderConfigs": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:            10 * time.Minute,
+					TestDirectory:      "/tmp/test-input.yaml",
+					TeardownScriptPath: "/tmp/teardown.sh",
+				},
+				resources: []config.Resource{
+					{
+						Name:      "example-vpc",
+						KindGroup: "vpc.ec2.aws.upbound.io",
+						Category:  config.CategoryManaged,
+						Tier:      1,
+					},
+					{
+						Name:      "aws-creds",
+						Namespace: "crossplane-system",
+						KindGroup: "secret",
+						Category:  config.CategoryKubernetes,
+					},
+					{
+						Name:      "default",
+						KindGroup: "providerconfig.aws.upbound.io",
+						Category:  config.CategoryProviderConfig,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the teardown step, which is executed after the delete
+# step and the leak check.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: teardown
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Provider Configs and Secrets
+    description: |
+      Delete the provider configs and then the Secrets, which are kept until
+      the managed resources are deleted and their external resources are
+      checked for leaks, as both need the credentials of the provider configs.
+    # The cleanup operations of a step are run at the end of the test, even
+    # if the step fails, so the teardown script always runs.
+    cleanup:
+    - command:
+        entrypoint: /tmp/teardown.sh
+    try:
+    - script:
+        content: |
+          ${KUBECTL} delete providerconfig.aws.upbound.io/default --ignore-not-found --timeout 10m0s
+    - script:
+        content: |
+          ${KUBECTL} delete --namespace crossplane-system secret/aws-creds --ignore-not-found --timeout 10m0s
+`,
+				rendered: true,
+			},
+		},
+		"NothingToTearDown": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:       10 * time.Minute,
+					TestDirectory: "/tmp/test-input.yaml",
+				},
+				resources: []config.Resource{
+					{
+						Name:      "example-vpc",
+						KindGroup: "vpc.ec2.aws.upbound.io",
+						Category:  config.CategoryManaged,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if err != nil {
+				t.Fatalf("Render(...): %v", err)
+			}
+			out, rendered := got["04-teardown.yaml"]
+			if rendered != tc.want.rendered {
+				t.Errorf("Render(...): want teardown rendered %t, got %t", tc.want.rendered, rendered)
+			}
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+			if strings.Contains(got["03-delete.yaml"], "providerconfig") || strings.Contains(got["03-delete.yaml"], "secret/") {
+				t.Errorf("Render(...): want the provider configs and Secrets to be left out of the delete step, got:\n%s", got["03-delete.yaml"])
+			}
+		})
+	}
+}
+
+func TestRenderOrderedApply(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"TwoTiers": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:          10 * time.Minute,
+					TestDirectory:    "test-input.yaml",
+					SkipWebhookCheck: true,
+					OrderedApply:     true,
+				},
+				resources: []config.Resource{
+					{
+						Name:       "example-subnet",
+						APIVersion: "ec2.aws.upbound.io/v1beta1",
+						Kind:       "Subnet",
+						KindGroup:  "subnet.ec2.aws.upbound.io",
+						Conditions: []string{"Ready"},
+						Tier:       1,
+						Category:   config.CategoryManaged,
+					},
+					{
+						Name:       "example-vpc",
+						APIVersion: "ec2.aws.upbound.io/v1beta1",
+						Kind:       "VPC",
+						KindGroup:  "vpc.ec2.aws.upbound.io",
+						Conditions: []string{"Ready"},
+						Category:   config.CategoryManaged,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource apply step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: apply
+spec:
+  timeouts:
+    apply: 10m0s
+    assert: 10m0s
+    exec: 10m0s
+  steps:
+  - name: Apply Resources (Tier 0)
+    description: Apply resources to the cluster.
+    try:
+    # The timings of a previous execution of the test case are discarded,
+    # as the timings are appended to the file.
+    - script:
+        content: rm -f tier-timings.log
+    - script:
+        content: date +%s > .tier-0-start
+    - apply:
+        file: test-input-tier-0.yaml
+    - script:
+        content: |
+          echo "Running annotation script with retry logic"
+          retry_annotate() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Annotation attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Annotation successful on attempt $attempt"
+                return 0
+              else
+                echo "Annotation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Annotation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_annotate "${KUBECTL} annotate  vpc.ec2.aws.upbound.io/example-vpc upjet.upbound.io/test=true --overwrite"
+  - name: Assert Status Conditions (Tier 0)
+    description: |
+      Assert applied resources. First, run the pre-assert script if exists.
+      Then, check the status conditions. Finally run the post-assert script if it
+      exists.
+    try:
+    - assert:
+        resource:
+          apiVersion: ec2.aws.upbound.io/v1beta1
+          kind: VPC
+          metadata:
+            name: example-vpc
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+    - script:
+        content: echo "0 $(( $(date +%s) - $(cat .tier-0-start) ))" >> tier-timings.log
+  - name: Apply Resources (Tier 1)
+    description: Apply resources to the cluster.
+    try:
+    - script:
+        content: date +%s > .tier-1-start
+    - apply:
+        file: test-input-tier-1.yaml
+    - script:
+        content: |
+          echo "Running annotation script with retry logic"
+          retry_annotate() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Annotation attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Annotation successful on attempt $attempt"
+                return 0
+              else
+                echo "Annotation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Annotation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_annotate "${KUBECTL} annotate  subnet.ec2.aws.upbound.io/example-subnet upjet.upbound.io/test=true --overwrite"
+  - name: Assert Status Conditions (Tier 1)
+    description: |
+      Assert applied resources. First, run the pre-assert script if exists.
+      Then, check the status conditions. Finally run the post-assert script if it
+      exists.
+    try:
+    - assert:
+        resource:
+          apiVersion: ec2.aws.upbound.io/v1beta1
+          kind: Subnet
+          metadata:
+            name: example-subnet
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+    - script:
+        content: echo "1 $(( $(date +%s) - $(cat .tier-1-start) ))" >> tier-timings.log
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, true)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["00-apply.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderComposite(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		apply  string
+		delete string
+		err    error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"Claim": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:                  10 * time.Minute,
+					TestDirectory:            "/tmp/test-input.yaml",
+					OnlyCleanUptestResources: true,
+					SkipWebhookCheck:         true,
+				},
+				resources: []config.Resource{
+					{
+						Name:       "example",
+						Namespace:  "default",
+						KindGroup:  "network.platform.example.org",
+						APIVersion: "platform.example.org/v1alpha1",
+						Kind:       "Network",
+						Category:   config.CategoryClaim,
+						Conditions: []string{"Ready"},
+					},
+				},
+			},
+			want: want{
+				apply: `# This file belongs to the resource apply step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: apply
+spec:
+  timeouts:
+    apply: 10m0s
+    assert: 10m0s
+    exec: 10m0s
+  steps:
+  - name: Apply Resources
+    description: Apply resources to the cluster.
+    try:
+    - apply:
+        file: /tmp/test-input.yaml
+    - script:
+        content: |
+          echo "Running annotation script with retry logic"
+          retry_annotate() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Annotation attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Annotation successful on attempt $attempt"
+                return 0
+              else
+                echo "Annotation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Annotation failed after $max_attempts attempts"
+            return 1
+          }
+  - name: Assert Status Conditions
+    description: |
+      Assert applied resources. First, run the pre-assert script if exists.
+      Then, check the status conditions. Finally run the post-assert script if it
+      exists.
+    try:
+    - assert:
+        resource:
+          apiVersion: platform.example.org/v1alpha1
+          kind: Network
+          metadata:
+            name: example
+            namespace: default
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+    - script:
+        content: |
+          # composed lists the apiVersion, kind, name and namespace of the
+          # resources composed by the specified composite resource.
+          composed() {
+            ${KUBECTL} get "$@" -o jsonpath='{range .spec.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}{range .spec.crossplane.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}'
+          }
+          # resource converts an apiVersion, kind and name to a resource
+          # argument of kubectl, e.g. bucket.v1beta1.s3.aws.upbound.io/example.
+          resource() {
+            group="${1%/*}"
+            version="${1##*/}"
+            if [ "$group" = "$1" ]; then
+              echo "$2/$3"
+            else
+              echo "$2.$version.$group/$3"
+            fi
+          }
+          target=$(resource $(${KUBECTL} get --namespace default network.platform.example.org/example -o jsonpath='{.spec.resourceRef.apiVersion} {.spec.resourceRef.kind} {.spec.resourceRef.name}'))
+          default_ns=""
+          composed $target | while read -r api kind name ns; do
+            [ -n "$name" ] || continue
+            ns="${ns:-$default_ns}"
+            echo "Waiting for the composed resource $kind/$name to become ready"
+            ${KUBECTL} wait --for=condition=Ready ${ns:+--namespace "$ns"} "$(resource "$api" "$kind" "$name")" --timeout 10m0s || exit 1
+          done
+`,
+				delete: `# This file belongs to the resource delete step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: delete
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Resources
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          # composed lists the apiVersion, kind, name and namespace of the
+          # resources composed by the specified composite resource.
+          composed() {
+            ${KUBECTL} get "$@" -o jsonpath='{range .spec.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}{range .spec.crossplane.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}'
+          }
+          # resource converts an apiVersion, kind and name to a resource
+          # argument of kubectl, e.g. bucket.v1beta1.s3.aws.upbound.io/example.
+          resource() {
+            group="${1%/*}"
+            version="${1##*/}"
+            if [ "$group" = "$1" ]; then
+              echo "$2/$3"
+            else
+              echo "$2.$version.$group/$3"
+            fi
+          }
+          target=$(resource $(${KUBECTL} get --namespace default network.platform.example.org/example -o jsonpath='{.spec.resourceRef.apiVersion} {.spec.resourceRef.kind} {.spec.resourceRef.name}'))
+          default_ns=""
+          composed $target | while read -r api kind name ns; do
+            if [ -n "$name" ]; then
+              echo "$api $kind $name ${ns:-$default_ns}"
+            fi
+          done > composed-network.platform.example.org-default-example.txt
+          retry_kubectl "${KUBECTL} delete network.platform.example.org/example --wait=false --namespace default --ignore-not-found"
+  - name: Assert Deletion
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --namespace default --for=delete network.platform.example.org/example --timeout 10m0s
+    - script:
+        content: |
+          # composed lists the apiVersion, kind, name and namespace of the
+          # resources composed by the specified composite resource.
+          composed() {
+            ${KUBECTL} get "$@" -o jsonpath='{range .spec.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}{range .spec.crossplane.resourceRefs[*]}{.apiVersion}{" "}{.kind}{" "}{.name}{" "}{.namespace}{"\n"}{end}'
+          }
+          # resource converts an apiVersion, kind and name to a resource
+          # argument of kubectl, e.g. bucket.v1beta1.s3.aws.upbound.io/example.
+          resource() {
+            group="${1%/*}"
+            version="${1##*/}"
+            if [ "$group" = "$1" ]; then
+              echo "$2/$3"
+            else
+              echo "$2.$version.$group/$3"
+            fi
+          }
+          while read -r api kind name ns; do
+            echo "Waiting for the composed resource $kind/$name to be deleted"
+            ${KUBECTL} wait --for=delete ${ns:+--namespace "$ns"} "$(resource "$api" "$kind" "$name")" --timeout 10m0s || exit 1
+          done < composed-network.platform.example.org-default-example.txt
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.apply, got["00-apply.yaml"]); diff != "" {
+				t.Errorf("Render(...): 00-apply.yaml: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.delete, got["03-delete.yaml"]); diff != "" {
+				t.Errorf("Render(...): 03-delete.yaml: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderFreshImport(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"ClusterScopedResource": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:       10 * time.Minute,
+					ImportTimeout: 20 * time.Minute,
+					TestDirectory: "/tmp/test-input.yaml",
+					ImportMode:    config.ImportModeFresh,
+				},
+				resources: []config.Resource{
+					{
+						Name:       "example-bucket",
+						KindGroup:  "bucket.s3.aws.upbound.io",
+						APIVersion: "bucket.s3.aws.upbound.io/v1alpha1",
+						Kind:       "Bucket",
+						Category:   config.CategoryManaged,
+						Conditions: []string{"Test"},
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource import step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: import
+spec:
+  timeouts:
+    apply: 20m0s
+    assert: 20m0s
+    exec: 20m0s
+  steps:
+  - name: Import Resources
+    description: |
+      Imports the MRs into fresh objects. First, the external name, the ID
+      and the spec.forProvider of each MR are recorded and the MR is deleted
+      while orphaning its external resource. Then, a new MR with only the
+      external name and the Observe management policy is created.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          external_name=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.metadata.annotations.crossplane\.io/external-name}')
+          id=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.status.atProvider.id}')
+          provider_config=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.providerConfigRef}')
+          for_provider=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.forProvider}')
+          spec='"managementPolicies":["Observe"]'
+          if [ -n "$provider_config" ]; then
+            spec="$spec,\"providerConfigRef\":$provider_config"
+          fi
+          if [ -n "$for_provider" ]; then
+            spec="$spec,\"forProvider\":$for_provider"
+          fi
+          ${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.forProvider}' > for-provider-bucket.s3.aws.upbound.io-example-bucket.json || exit 1
+          echo "{\"apiVersion\":\"bucket.s3.aws.upbound.io/v1alpha1\",\"kind\":\"Bucket\",\"metadata\":{\"name\":\"example-bucket\",\"annotations\":{\"crossplane.io/external-name\":\"$external_name\",\"uptest-old-id\":\"$id\",\"upjet.upbound.io/test\":\"true\"}},\"spec\":{$spec}}" > import-bucket.s3.aws.upbound.io-example-bucket.json
+          retry_kubectl "${KUBECTL} patch bucket.s3.aws.upbound.io/example-bucket --type=merge -p '{\"spec\":{\"deletionPolicy\":\"Orphan\"}}'"
+          retry_kubectl "${KUBECTL} delete bucket.s3.aws.upbound.io/example-bucket --ignore-not-found --timeout 20m0s"
+          retry_kubectl "${KUBECTL} create -f import-bucket.s3.aws.upbound.io-example-bucket.json"
+  - name: Assert Status Conditions and IDs
+    description: |
+      Assert imported resources. Firstly check the status conditions. Then
+      compare the recorded ID and the new populated ID and check that the
+      observed state is populated. For successful test, the ID must be the
+      same.
+    try:
+    - assert:
+        resource:
+          apiVersion: bucket.s3.aws.upbound.io/v1alpha1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          status:
+            ((conditions[?type == 'Test'])[0]):
+              status: "True"
+    - assert:
+        timeout: 20m0s
+        resource:
+          apiVersion: bucket.s3.aws.upbound.io/v1alpha1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          (status.atProvider.id == metadata.annotations."uptest-old-id"): true
+          (status.atProvider != null): true
+  - name: Re-adopt Resources
+    description: |
+      Re-adopts the external resources by granting the imported MRs full
+      control, so that they are deleted with the external resources in the
+      delete step. The recorded spec.forProvider is restored in the same
+      patch, as the required parameters must be set with full control.
+    try:
+    - script:
+        content: |
+          for_provider=$(cat for-provider-bucket.s3.aws.upbound.io-example-bucket.json)
+          [ -n "$for_provider" ] || for_provider='{}'
+          ${KUBECTL} patch bucket.s3.aws.upbound.io/example-bucket --type=merge -p "{\"spec\":{\"managementPolicies\":[\"*\"],\"forProvider\":$for_provider}}"
+    - assert:
+        resource:
+          apiVersion: bucket.s3.aws.upbound.io/v1alpha1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          status:
+            ((conditions[?type == 'Test'])[0]):
+              status: "True"
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["02-import.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderObserveOnly(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"NamespacedResource": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:         10 * time.Minute,
+					TestDirectory:   "/tmp/test-input.yaml",
+					ObserveOnlyTest: true,
+				},
+				resources: []config.Resource{
+					{
+						Name:       "example-bucket",
+						Namespace:  "default",
+						KindGroup:  "bucket.s3.aws.m.upbound.io",
+						APIVersion: "s3.aws.m.upbound.io/v1beta1",
+						Kind:       "Bucket",
+						Category:   config.CategoryManaged,
+						Conditions: []string{"Ready"},
+						// The observe-only step does not depend on the
+						// import step.
+						SkipImport: true,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the observe-only step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: observe
+spec:
+  timeouts:
+    apply: 10m0s
+    assert: 10m0s
+    exec: 10m0s
+  steps:
+  - name: Create Observe-Only Twins
+    description: |
+      Creates an observe-only twin of each MR with the same external name.
+      The ID of the external resource is stored in the uptest-observe-id
+      annotation of both the MR and its twin.
+    try:
+    - script:
+        content: |
+          external_name=$(${KUBECTL} get --namespace default bucket.s3.aws.m.upbound.io/example-bucket -o jsonpath='{.metadata.annotations.crossplane\.io/external-name}')
+          id=$(${KUBECTL} get --namespace default bucket.s3.aws.m.upbound.io/example-bucket -o jsonpath='{.status.atProvider.id}')
+          provider_config=$(${KUBECTL} get --namespace default bucket.s3.aws.m.upbound.io/example-bucket -o jsonpath='{.spec.providerConfigRef}')
+          for_provider=$(${KUBECTL} get --namespace default bucket.s3.aws.m.upbound.io/example-bucket -o jsonpath='{.spec.forProvider}')
+          spec='"managementPolicies":["Observe"]'
+          if [ -n "$provider_config" ]; then
+            spec="$spec,\"providerConfigRef\":$provider_config"
+          fi
+          if [ -n "$for_provider" ]; then
+            spec="$spec,\"forProvider\":$for_provider"
+          fi
+          ${KUBECTL} annotate --namespace default bucket.s3.aws.m.upbound.io/example-bucket uptest-observe-id="$id" --overwrite
+          echo "{\"apiVersion\":\"s3.aws.m.upbound.io/v1beta1\",\"kind\":\"Bucket\",\"metadata\":{\"name\":\"example-bucket-observe\",\"namespace\":\"default\",\"annotations\":{\"crossplane.io/external-name\":\"$external_name\",\"uptest-observe-id\":\"$id\",\"upjet.upbound.io/test\":\"true\"}},\"spec\":{$spec}}" | ${KUBECTL} create -f -
+  - name: Assert Observe-Only Twins
+    description: |
+      Assert the observe-only twins. Firstly check the status conditions. Then
+      check that the observed state is populated with the ID of the external
+      resource of the MR.
+    try:
+    - assert:
+        resource:
+          apiVersion: s3.aws.m.upbound.io/v1beta1
+          kind: Bucket
+          metadata:
+            name: example-bucket-observe
+            namespace: default
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+    - assert:
+        timeout: 10m0s
+        resource:
+          apiVersion: s3.aws.m.upbound.io/v1beta1
+          kind: Bucket
+          metadata:
+            name: example-bucket-observe
+            namespace: default
+          (status.atProvider != null): true
+          (status.atProvider.id == metadata.annotations."uptest-observe-id"): true
+  - name: Delete Observe-Only Twins
+    description: |
+      Delete the observe-only twins, which must not delete the external
+      resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} delete --namespace default bucket.s3.aws.m.upbound.io/example-bucket-observe --timeout 10m0s
+  - name: Assert Original Resources
+    description: |
+      Assert that the external resources still exist. The status conditions
+      of the MRs are cleared, so that they are observed again, and the MRs
+      must become ready with the same IDs as before.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} patch --namespace default bucket.s3.aws.m.upbound.io/example-bucket --subresource=status --type=merge -p '{"status":{"conditions":[]}}'
+    - assert:
+        resource:
+          apiVersion: s3.aws.m.upbound.io/v1beta1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+            namespace: default
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+          (status.atProvider.id == metadata.annotations."uptest-observe-id"): true
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["00-observe.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderDrift(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"DriftParameterAndHook": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:       10 * time.Minute,
+					TestDirectory: "/tmp/test-input.yaml",
+					DriftTest:     true,
+				},
+				resources: []config.Resource{
+					{
+						Name:            "example-bucket",
+						KindGroup:       "bucket.s3.aws.upbound.io",
+						APIVersion:      "s3.aws.upbound.io/v1beta1",
+						Kind:            "Bucket",
+						Category:        config.CategoryManaged,
+						Conditions:      []string{"Ready"},
+						DriftScriptPath: "/tmp/bucket/drift.sh",
+						DriftPatch:      `{"spec":{"forProvider":{"tags":{"drift":"true"}}}}`,
+						DriftAssertions: []config.DriftAssertion{
+							{Path: ".status.atProvider.tags.drift", DesiredPath: ".spec.forProvider.tags.drift", Value: "true"},
+						},
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the drift detection step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: drift
+spec:
+  timeouts:
+    apply: 10m0s
+    assert: 10m0s
+    exec: 10m0s
+  steps:
+  - name: Introduce Drift
+    description: |
+      Introduce drift to the external resources of the MRs. The MRs are paused
+      and their external resources are changed either by the drift hook or by
+      a twin MR with the Observe and Update management policies, which is
+      deleted after the change is observed.
+    try:
+    - script:
+        content: |
+          uid=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.metadata.uid}')
+          ${KUBECTL} annotate bucket.s3.aws.upbound.io/example-bucket crossplane.io/paused=true --overwrite
+          ${KUBECTL} get events --namespace default --field-selector involvedObject.uid=$uid,reason=UpdatedExternalResource -o jsonpath='{range .items[*]}{.count}{"\n"}{end}' | awk '{ s += ($1 > 0 ? $1 : 1) } END { print s + 0 }' > drift-bucket.s3.aws.upbound.io-example-bucket.txt
+          /tmp/bucket/drift.sh
+          external_name=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.metadata.annotations.crossplane\.io/external-name}')
+          provider_config=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.providerConfigRef}')
+          for_provider=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.forProvider}')
+          spec='"managementPolicies":["Observe","Update"]'
+          if [ -n "$provider_config" ]; then
+            spec="$spec,\"providerConfigRef\":$provider_config"
+          fi
+          if [ -n "$for_provider" ]; then
+            spec="$spec,\"forProvider\":$for_provider"
+          fi
+          echo "{\"apiVersion\":\"s3.aws.upbound.io/v1beta1\",\"kind\":\"Bucket\",\"metadata\":{\"name\":\"example-bucket-drift\",\"annotations\":{\"crossplane.io/external-name\":\"$external_name\",\"upjet.upbound.io/test\":\"true\"}},\"spec\":{$spec}}" | ${KUBECTL} create -f - || exit 1
+          ${KUBECTL} patch bucket.s3.aws.upbound.io/example-bucket-drift --type=merge -p '{"spec":{"forProvider":{"tags":{"drift":"true"}}}}' || exit 1
+          until ${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket-drift -o=jsonpath='{.status.atProvider.tags.drift}' | grep -Fxq -- 'true'; do
+            echo "Waiting for the drift of .status.atProvider.tags.drift to be observed"
+            sleep 5
+          done
+          ${KUBECTL} delete bucket.s3.aws.upbound.io/example-bucket-drift --timeout 10m0s
+  - name: Assert Drift Correction
+    description: |
+      Assert that the drift is detected and corrected. The MRs are unpaused
+      and must detect the drift, which is reported either with the UpToDate
+      condition turning False or with an UpdatedExternalResource event of the
+      MR. Then the MRs must become up to date again, the status conditions
+      are checked and the drifted fields must be reconciled back to
+      spec.forProvider.
+    try:
+    - script:
+        content: |
+          uid=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.metadata.uid}')
+          ${KUBECTL} annotate bucket.s3.aws.upbound.io/example-bucket crossplane.io/paused-
+          before=$(cat drift-bucket.s3.aws.upbound.io-example-bucket.txt)
+          until [ "$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.status.conditions[?(@.type=="UpToDate")].status}')" = "False" ] || [ "$(${KUBECTL} get events --namespace default --field-selector involvedObject.uid=$uid,reason=UpdatedExternalResource -o jsonpath='{range .items[*]}{.count}{"\n"}{end}' | awk '{ s += ($1 > 0 ? $1 : 1) } END { print s + 0 }')" -gt "$before" ]; do
+            echo "Waiting for the drift of bucket.s3.aws.upbound.io/example-bucket to be detected"
+            sleep 5
+          done
+          until [ "$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.status.conditions[?(@.type=="UpToDate")].status}')" != "False" ]; do
+            echo "Waiting for bucket.s3.aws.upbound.io/example-bucket to be up to date"
+            sleep 5
+          done
+    - assert:
+        resource:
+          apiVersion: s3.aws.upbound.io/v1beta1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+    - script:
+        content: |
+          until [ "$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o=jsonpath='{.status.atProvider.tags.drift}')" = "$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o=jsonpath='{.spec.forProvider.tags.drift}')" ]; do
+            echo "Waiting for .status.atProvider.tags.drift to be reconciled back to .spec.forProvider.tags.drift"
+            sleep 5
+          done
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["00-drift.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderVerifyOrphan(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"ClusterScopedResource": {
+			args: args{
+				tc: &config.TestCase{
+					Timeout:                  10 * time.Minute,
+					TestDirectory:            "/tmp/test-input.yaml",
+					OnlyCleanUptestResources: true,
+					RunID:                    "abcde12345",
+				},
+				resources: []config.Resource{
+					{
+						Name:         "example-bucket",
+						KindGroup:    "bucket.s3.aws.upbound.io",
+						APIVersion:   "s3.aws.upbound.io/v1beta1",
+						Kind:         "Bucket",
+						Category:     config.CategoryManaged,
+						Conditions:   []string{"Ready"},
+						VerifyOrphan: true,
+					},
+				},
+			},
+			want: want{
+				out: `# This file belongs to the resource delete step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: delete
+spec:
+  timeouts:
+    exec: 10m0s
+  steps:
+  - name: Delete Resources
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          external_name=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.metadata.annotations.crossplane\.io/external-name}')
+          id=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.status.atProvider.id}')
+          provider_config=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.providerConfigRef}')
+          for_provider=$(${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket -o jsonpath='{.spec.forProvider}')
+          spec='"managementPolicies":["Observe"]'
+          if [ -n "$provider_config" ]; then
+            spec="$spec,\"providerConfigRef\":$provider_config"
+          fi
+          if [ -n "$for_provider" ]; then
+            spec="$spec,\"forProvider\":$for_provider"
+          fi
+          echo "{\"apiVersion\":\"s3.aws.upbound.io/v1beta1\",\"kind\":\"Bucket\",\"metadata\":{\"name\":\"example-bucket\",\"labels\":{\"uptest.upbound.io/run-id\":\"abcde12345\"},\"annotations\":{\"crossplane.io/external-name\":\"$external_name\",\"uptest-orphan-id\":\"$id\",\"upjet.upbound.io/test\":\"true\"}},\"spec\":{$spec}}" > orphan-bucket.s3.aws.upbound.io-example-bucket.json
+          retry_kubectl "${KUBECTL} patch bucket.s3.aws.upbound.io/example-bucket --type=merge -p '{\"spec\":{\"deletionPolicy\":\"Orphan\"}}'"
+          retry_kubectl "${KUBECTL} delete bucket.s3.aws.upbound.io/example-bucket --wait=false --ignore-not-found"
+  - name: Assert Deletion
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --for=delete bucket.s3.aws.upbound.io/example-bucket --timeout 10m0s
+  - name: Verify Orphaned Resources
+    description: |
+      Verify that the external resources of the MRs deleted with the Orphan
+      deletion policy still exist, by observing them with new Observe-only
+      MRs. Then clean the external resources up by deleting the new MRs with
+      the Delete management policy.
+    # The finally operations of a step are run even if the step fails, so the
+    # external resources are cleaned up even if they could not be observed.
+    finally:
+    - script:
+        content: |
+          if ${KUBECTL} get bucket.s3.aws.upbound.io/example-bucket > /dev/null; then
+            ${KUBECTL} patch bucket.s3.aws.upbound.io/example-bucket --type=merge -p '{"spec":{"managementPolicies":["Observe","Delete"]}}' || exit 1
+            ${KUBECTL} delete bucket.s3.aws.upbound.io/example-bucket --timeout 10m0s
+          fi
+    try:
+    - script:
+        content: |
+          ${KUBECTL} create -f orphan-bucket.s3.aws.upbound.io-example-bucket.json
+    - assert:
+        resource:
+          apiVersion: s3.aws.upbound.io/v1beta1
+          kind: Bucket
+          metadata:
+            name: example-bucket
+          status:
+            ((conditions[?type == 'Ready'])[0]):
+              status: "True"
+          (status.atProvider.id == metadata.annotations."uptest-orphan-id"): true
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["03-delete.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderPhaseTimeouts(t *testing.T) {
+	type args struct {
+		tc        *config.TestCase
+		resources []config.Resource
+	}
+	type want struct {
+		out string
+		err error
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"DeleteTimeout": {
+			args: args{
+				tc:        &config.TestCase{Timeout: 10 * time.Minute, DeleteTimeout: 30 * time.Minute, CleanupTimeout: 10 * time.Minute, RunID: "abc"},
+				resources: []config.Resource{{Name: "example-bucket", KindGroup: "bucket.s3.aws.upbound.io", APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Category: config.CategoryManaged, Timeout: 10 * time.Minute, Conditions: []string{"Ready"}}},
+			},
+			want: want{
+				out: `# This file belongs to the resource delete step.
+apiVersion: chainsaw.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: delete
+spec:
+  timeouts:
+    exec: 30m0s
+  steps:
+  - name: Delete Resources
+    description: Delete resources. If needs ordered deletion, the pre-delete scripts were used.
+    try:
+    - script:
+        content: |
+          retry_kubectl() {
+            local max_attempts=10
+            local delay=5
+            local attempt=1
+            local cmd="$1"
+
+            while [ $attempt -le $max_attempts ]; do
+              echo "Kubectl attempt $attempt/$max_attempts for: $cmd"
+              if eval "$cmd"; then
+                echo "Kubectl operation successful on attempt $attempt"
+                return 0
+              else
+                echo "Kubectl operation failed on attempt $attempt"
+                if [ $attempt -lt $max_attempts ]; then
+                  echo "Retrying in ${delay}s..."
+                  sleep $delay
+                fi
+                ((attempt++))
+              fi
+            done
+            echo "Kubectl operation failed after $max_attempts attempts"
+            return 1
+          }
+          retry_kubectl "${KUBECTL} delete bucket.s3.aws.upbound.io/example-bucket --wait=false --ignore-not-found"
+  - name: Assert Deletion
+    description: Assert deletion of resources.
+    try:
+    - script:
+        content: |
+          ${KUBECTL} wait --for=delete bucket.s3.aws.upbound.io/example-bucket --timeout 30m0s
+    - script:
+        timeout: 10m0s
+        content: |
+          ${KUBECTL} wait managed --all-namespaces --selector uptest.upbound.io/run-id=abc --for=delete --timeout 10m0s
+`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.tc, tc.args.resources, false)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Render(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got["03-delete.yaml"]); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
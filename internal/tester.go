@@ -6,20 +6,22 @@ package internal
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/alecthomas/kong"
+	"github.com/go-logr/logr"
 	"github.com/kyverno/chainsaw/pkg/discovery"
 	kconfig "github.com/kyverno/chainsaw/pkg/loaders/config"
 	"github.com/kyverno/chainsaw/pkg/runner"
@@ -27,22 +29,62 @@ import (
 	runnerflags "github.com/kyverno/chainsaw/pkg/runner/flags"
 	restutils "github.com/kyverno/chainsaw/pkg/utils/rest"
 	"github.com/kyverno/pkg/ext/output/color"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
-	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
-	"github.com/crossplane/crossplane/v2/cmd/crank/beta/trace"
 
+	"github.com/crossplane/uptest/v2/internal/cluster"
 	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/graph"
+	"github.com/crossplane/uptest/v2/internal/logging"
 	"github.com/crossplane/uptest/v2/internal/templates"
 )
 
+const (
+	// LogsDirectory is the directory under the test directory the raw
+	// chainsaw output of each phase and the history of each resource are
+	// written to.
+	LogsDirectory = "logs"
+	// phaseLogsDirectory is the directory under the logs directory the raw
+	// chainsaw output of each phase is written to.
+	phaseLogsDirectory = "phases"
+	// resourceLogsDirectory is the directory under the logs directory the
+	// history of each resource is written to.
+	resourceLogsDirectory = "resources"
+
+	// stabilityPhase is the name of the stability check in the report.
+	stabilityPhase = "stability"
+	// stabilityPollInterval is the interval the managed resources are
+	// polled at during the stability check.
+	stabilityPollInterval = 5 * time.Second
+	// leakCheckPhase is the name of the leaked external resource check in
+	// the report.
+	leakCheckPhase = "leak-check"
+	// cleanupPhase is the name of the delete step executed in the report,
+	// when it's executed after a failed or interrupted phase.
+	cleanupPhase = "cleanup"
+	// teardownFile is the test file that deletes the provider configs and
+	// runs the teardown script. It's executed after the delete step and the
+	// leak check, which need the provider configs, whenever the delete step
+	// is executed.
+	teardownFile = "04-teardown.yaml"
+	// leakCheckPollInterval is the interval the Observe-only managed
+	// resources are polled at during the leak check.
+	leakCheckPollInterval = 5 * time.Second
+)
+
 var testFiles = []string{
 	"00-apply.yaml",
+	"00-observe.yaml",
+	"00-drift.yaml",
 	"01-update.yaml",
 	"02-import.yaml",
 	"03-delete.yaml",
@@ -61,52 +103,546 @@ func NewTester(ms []config.Manifest, opts *config.AutomatedTest) *Tester {
 type Tester struct {
 	options   *config.AutomatedTest
 	manifests []config.Manifest
+
+	// state is the state of the run, which is persisted after each phase.
+	state *runState
+	// resumed is the state of the resumed run, whose passed phases are
+	// skipped until the first phase that did not pass.
+	resumed *runState
 }
 
 // ExecuteTests execute tests via chainsaw.
 func (t *Tester) ExecuteTests(ctx context.Context) error {
+	// The state of a previous run in the directory does not match the test
+	// case rendered below anymore.
+	if err := os.Remove(filepath.Join(t.options.Directory, stateFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "cannot remove the state of the previous run")
+	}
 	if err := writeTestFile(t.manifests, t.options.Directory); err != nil {
 		return errors.Wrap(err, "cannot write test manifest files")
 	}
 
-	resources, timeout, err := t.writeChainsawFiles()
+	resources, timeouts, err := t.writeChainsawFiles(ctx)
 	if err != nil {
 		return errors.Wrap(err, "cannot write chainsaw test files")
 	}
 
-	log.Printf("Written test files: %s\n", t.options.Directory)
+	slog.InfoContext(ctx, "Written test files", "directory", t.options.Directory)
 
 	if t.options.RenderOnly {
 		return nil
 	}
+	logsDir := filepath.Join(t.options.Directory, LogsDirectory)
+	if err := os.RemoveAll(logsDir); err != nil {
+		return errors.Wrapf(err, "cannot clean the logs directory %s", logsDir)
+	}
+	t.state = &runState{RunID: t.options.RunID}
+	return t.execute(ctx, resources, timeouts, testFiles)
+}
 
-	log.Println("Running chainsaw tests at " + t.options.Directory)
-	startTime := time.Now()
+// Resume resumes the run whose state is persisted in the test directory by
+// a previous run. The test case rendered by that run is executed again, and
+// the phases that passed are skipped until the first phase that did not
+// pass, if the tested resources still exist. Otherwise all phases are
+// executed again. It returns false without executing anything if there is no
+// run to resume.
+func (t *Tester) Resume(ctx context.Context) (bool, error) {
+	st, err := readState(t.options.Directory)
+	if err != nil || st == nil {
+		return false, err
+	}
+	t.options.RunID = st.RunID
+	ctx = logging.WithAttrs(ctx, logging.KeyRunID, st.RunID)
+	ms, err := readTestFile(t.options.Directory)
+	if err != nil {
+		return true, errors.Wrap(err, "cannot read the test manifest file")
+	}
+	t.manifests = ms
+	tc, resources, err := t.prepareConfig(ctx)
+	if err != nil {
+		return true, errors.Wrap(err, "cannot build examples config")
+	}
+	exist, err := resourcesExist(ctx, resources)
+	if err != nil {
+		return true, errors.Wrap(err, "cannot check whether the resources of the run exist")
+	}
+	t.state = st
+	if exist {
+		t.resumed = &runState{RunID: st.RunID, Phases: append([]phaseState(nil), st.Phases...)}
+		slog.InfoContext(ctx, "Resuming the run from the first phase that did not pass")
+	} else {
+		slog.InfoContext(ctx, "Resources of the run do not exist anymore, executing all phases")
+	}
+	return true, t.execute(ctx, resources, phaseTimeouts(tc), testFiles)
+}
+
+// resourcesExist returns whether all the resources exist.
+func resourcesExist(ctx context.Context, resources []config.Resource) (bool, error) {
+	c, err := cluster.New()
+	if err != nil {
+		return false, errors.Wrap(err, "cannot create the control plane client")
+	}
+	for _, r := range resources {
+		ok, err := c.Exists(ctx, r)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// skip returns whether the phase passed in the resumed run and can be
+// skipped. No phase is skipped after the first one that did not pass.
+func (t *Tester) skip(ctx context.Context, phase string) bool {
+	if t.resumed == nil {
+		return false
+	}
+	if !t.resumed.passed(phase) {
+		t.resumed = nil
+		return false
+	}
+	slog.InfoContext(logging.WithAttrs(ctx, logging.KeyPhase, phase), "Skipping the phase, which passed in the resumed run")
+	return true
+}
+
+// record records the result of the phase in the state of the run and
+// persists it. Failing to persist the state does not change the result of
+// the test, so the error is only logged.
+func (t *Tester) record(ctx context.Context, phase string, err error) {
+	if t.state == nil {
+		return
+	}
+	t.state.setPhase(phase, err)
+	if err := writeState(t.options.Directory, t.state); err != nil {
+		slog.WarnContext(ctx, "Cannot persist the run state", "error", err)
+	}
+}
+
+// ExecuteRendered executes the chainsaw test case already rendered in the
+// test directory, or only the specified phase of it, without preparing the
+// manifests or rendering the test files again. The resources are read from
+// the test input file of the case. All phases are executed if phase is
+// empty.
+func (t *Tester) ExecuteRendered(ctx context.Context, phase string) error {
+	ms, err := readTestFile(t.options.Directory)
+	if err != nil {
+		return errors.Wrap(err, "cannot read the test manifest file")
+	}
+	t.manifests = ms
+	if t.options.RunID == "" {
+		t.options.RunID = runIDOf(ms)
+	}
+	if t.options.RunID != "" {
+		ctx = logging.WithAttrs(ctx, logging.KeyRunID, t.options.RunID)
+	}
+	tc, resources, err := t.prepareConfig(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot build examples config")
+	}
+	if t.state, err = readState(t.options.Directory); err != nil {
+		return err
+	}
+	if t.state == nil {
+		t.state = &runState{RunID: t.options.RunID}
+	}
+	files := testFiles
+	if phase != "" {
+		tf, err := phaseFile(phase)
+		if err != nil {
+			return err
+		}
+		if !checkFileExists(filepath.Join(t.options.Directory, caseDirectory, tf)) {
+			return errors.Errorf("phase %q is not rendered in %s", phase, t.options.Directory)
+		}
+		files = []string{tf}
+	}
+	return t.execute(ctx, resources, phaseTimeouts(tc), files)
+}
+
+// phaseFile returns the test file of the phase, which is either the name of
+// the test file, e.g. 02-import.yaml, or the name of the step, e.g. import.
+func phaseFile(phase string) (string, error) {
+	names := make([]string, 0, len(testFiles))
 	for _, tf := range testFiles {
+		name := strings.TrimSuffix(tf[strings.Index(tf, "-")+1:], filepath.Ext(tf))
+		if phase == tf || phase == name {
+			return tf, nil
+		}
+		names = append(names, name)
+	}
+	return "", errors.Errorf("unknown phase %q, expected one of %s", phase, strings.Join(names, ", "))
+}
+
+// execute executes the specified test files of the test case in order, each
+// within its own time budget, and stops at the first failing one. If a phase
+// fails or the run is interrupted before the delete step completes, the
+// delete step is executed to clean up, if it's one of the test files. The
+// teardown step is executed whenever the delete step is, after the leak
+// check.
+func (t *Tester) execute(ctx context.Context, resources []config.Resource, timeouts map[string]time.Duration, files []string) (err error) {
+	slog.InfoContext(ctx, "Running chainsaw tests", "directory", t.options.Directory)
+	logsDir := filepath.Join(t.options.Directory, LogsDirectory)
+	rep := &report{}
+	defer rep.print(ctx)
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	w, werr := watch(watchCtx, resources, t.options.RunID, t.options.LogCollectionInterval, filepath.Join(logsDir, resourceLogsDirectory))
+	if werr != nil {
+		slog.WarnContext(ctx, "Cannot watch the resources", "error", werr)
+	}
+	if w != nil {
+		defer func() {
+			if err := w.Close(); err != nil {
+				slog.WarnContext(ctx, "Cannot close the resource histories", "error", err)
+			}
+		}()
+	}
+	prog, stopProgress := t.startProgress(watchCtx, w)
+	defer stopProgress()
+	// deleted is whether the delete step completed without being
+	// interrupted, successfully or not.
+	deleted := false
+	defer func() {
+		// The progress table is not redrawn anymore once the run is
+		// interrupted, so the logs of the cleanup are shown instead.
+		if ctx.Err() != nil {
+			stopProgress()
+		}
+		if err != nil && !deleted && slices.Contains(files, "03-delete.yaml") && checkFileExists(filepath.Join(t.options.Directory, caseDirectory, "03-delete.yaml")) {
+			// The resources of a resumable run are kept, as the resumed run
+			// skips the phases that passed only if they still exist.
+			if t.options.NoCleanupOnFailure || t.options.Resume {
+				slog.WarnContext(ctx, "Not cleaning up the resources of the failed run", "directory", t.options.Directory, "resume", t.options.Resume)
+				return
+			}
+			prog.setPhase(cleanupPhase)
+			err = t.cleanUp(ctx, rep, resources, timeouts, err)
+			deleted = true
+		}
+		if deleted {
+			prog.setPhase(teardownFile)
+			err = t.tearDown(ctx, rep, timeouts[teardownFile], err)
+		}
+	}()
+	startTime := time.Now()
+	for _, tf := range files {
+		ctx := logging.WithAttrs(ctx, logging.KeyPhase, tf)
 		if !checkFileExists(filepath.Join(t.options.Directory, caseDirectory, tf)) {
-			log.Println("Skipping test " + tf)
+			slog.InfoContext(ctx, "Skipping test")
 			continue
 		}
-		if err := executeSingleTestFile(ctx, t, tf, timeout-time.Since(startTime), resources); err != nil {
-			return errors.Wrap(err, "cannot execute test "+tf)
+		var refs []cluster.ExternalRef
+		if !t.skip(ctx, tf) {
+			prog.setPhase(tf)
+			phaseStart := time.Now()
+			deadline := phaseStart.Add(timeouts[tf])
+			var err error
+			if tf == "02-import.yaml" && t.options.ImportMode != config.ImportModeFresh {
+				err = restartProviders(ctx, resources, time.Until(deadline))
+			}
+			if tf == "03-delete.yaml" && !t.options.SkipLeakCheck {
+				refs, err = recordExternalRefs(ctx, resources)
+			}
+			if err == nil {
+				err = executeSingleTestFile(ctx, t, tf, tf, time.Until(deadline))
+			}
+			if tf == "03-delete.yaml" {
+				deleted = ctx.Err() == nil
+			}
+			rep.addPhase(tf, time.Since(phaseStart), err)
+			t.record(ctx, tf, err)
+			if tf == testFiles[0] && t.options.OrderedApply {
+				tiers, terr := readTierTimings(filepath.Join(t.options.Directory, caseDirectory, templates.TierTimingsFile))
+				if terr != nil {
+					slog.WarnContext(ctx, "Cannot read the tier timings", "error", terr)
+				}
+				rep.tiers = tiers
+			}
+			if err != nil {
+				t.logDiagnostics(ctx, tf, resources, startTime)
+				return errors.Wrap(err, "cannot execute test "+tf)
+			}
+		}
+		if tf == testFiles[0] && t.options.StabilityWindow > 0 && !t.skip(ctx, stabilityPhase) {
+			prog.setPhase(stabilityPhase)
+			ctx := logging.WithAttrs(ctx, logging.KeyPhase, stabilityPhase)
+			phaseStart := time.Now()
+			details, err := checkStability(ctx, resources, t.options.StabilityWindow)
+			rep.addPhase(stabilityPhase, time.Since(phaseStart), err, details...)
+			t.record(ctx, stabilityPhase, err)
+			if err != nil {
+				t.logDiagnostics(ctx, stabilityPhase, resources, startTime)
+				return errors.Wrap(err, "cannot execute the stability check")
+			}
+		}
+		if len(refs) > 0 {
+			prog.setPhase(leakCheckPhase)
+			ctx := logging.WithAttrs(ctx, logging.KeyPhase, leakCheckPhase)
+			phaseStart := time.Now()
+			details, err := checkLeaks(ctx, refs, timeouts[leakCheckPhase])
+			rep.addPhase(leakCheckPhase, time.Since(phaseStart), err, details...)
+			t.record(ctx, leakCheckPhase, err)
+			if err != nil {
+				t.logDiagnostics(ctx, leakCheckPhase, resources, startTime)
+				return errors.Wrap(err, "cannot execute the leak check")
+			}
 		}
 	}
 	return nil
 }
 
-func executeSingleTestFile(ctx context.Context, t *Tester, tf string, timeout time.Duration, resources []config.Resource) error {
+// cleanUp executes the delete step after a failed or interrupted phase, so
+// that the resources of the run are not left behind. It's executed with a
+// context that is not canceled with the run, within the time budget of the
+// delete step. The managed resources are prepared for deletion first, i.e.
+// unpaused and given full control over their external resources, which the
+// failed phase may have taken away. Unless disabled, the external resources
+// are then checked for leaks like after the delete step. The returned error
+// is the error of the failed phase, joined with the errors of the delete step
+// and the leak check if they fail.
+func (t *Tester) cleanUp(ctx context.Context, rep *report, resources []config.Resource, timeouts map[string]time.Duration, cause error) error {
+	ctx = logging.WithAttrs(context.WithoutCancel(ctx), logging.KeyPhase, cleanupPhase)
+	slog.WarnContext(ctx, "Executing the delete step to clean up the resources of the failed run", "error", cause)
+	start := time.Now()
+	if c, err := cluster.New(); err != nil {
+		slog.WarnContext(ctx, "Cannot create the control plane client to prepare the resources for deletion", "error", err)
+	} else {
+		for _, r := range resources {
+			if !r.IsManaged() {
+				continue
+			}
+			if err := c.PrepareDeletion(ctx, r); err != nil {
+				slog.WarnContext(ctx, "Cannot prepare the managed resource for deletion", logging.Resource(r.KindGroup, r.Namespace, r.Name), "error", err)
+			}
+		}
+	}
+	var refs []cluster.ExternalRef
+	if !t.options.SkipLeakCheck {
+		var err error
+		if refs, err = recordExternalRefs(ctx, resources); err != nil {
+			slog.WarnContext(ctx, "Cannot record the external resources, not checking them for leaks", "error", err)
+		}
+	}
+	err := executeSingleTestFile(ctx, t, "03-delete.yaml", cleanupPhase, timeouts["03-delete.yaml"])
+	rep.addPhase(cleanupPhase, time.Since(start), err)
+	if err != nil {
+		return errors.Join(cause, errors.Wrap(err, "cannot clean up the resources of the run"))
+	}
+	if len(refs) == 0 {
+		return cause
+	}
+	ctx = logging.WithAttrs(ctx, logging.KeyPhase, leakCheckPhase)
+	start = time.Now()
+	details, err := checkLeaks(ctx, refs, timeouts[leakCheckPhase])
+	rep.addPhase(leakCheckPhase, time.Since(start), err, details...)
+	if err != nil {
+		return errors.Join(cause, errors.Wrap(err, "cannot execute the leak check"))
+	}
+	return cause
+}
+
+// tearDown executes the teardown step after the delete step, which deletes
+// the provider configs and runs the teardown script, if it's rendered. Like
+// the cleanup, it's executed with a context that is not canceled with the
+// run. The returned error is the error of the run, joined with the error of
+// the teardown step if it fails.
+func (t *Tester) tearDown(ctx context.Context, rep *report, timeout time.Duration, cause error) error {
+	if !checkFileExists(filepath.Join(t.options.Directory, caseDirectory, teardownFile)) {
+		return cause
+	}
+	ctx = logging.WithAttrs(context.WithoutCancel(ctx), logging.KeyPhase, teardownFile)
+	start := time.Now()
+	err := executeSingleTestFile(ctx, t, teardownFile, teardownFile, timeout)
+	rep.addPhase(teardownFile, time.Since(start), err)
+	if err != nil {
+		return errors.Join(cause, errors.Wrap(err, "cannot execute test "+teardownFile))
+	}
+	return cause
+}
+
+// watch starts watching the tested resources, which logs their condition
+// transitions and Warning events until the context is done. They are also
+// recorded in the history file of each resource in historyDir. Failing to
+// record the histories does not prevent watching, so the error is only
+// logged.
+func watch(ctx context.Context, resources []config.Resource, runID string, summaryInterval time.Duration, historyDir string) (*cluster.Watcher, error) {
+	c, err := cluster.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the control plane client")
+	}
+	w := cluster.NewWatcher(resources)
+	if err := w.RecordHistory(historyDir); err != nil {
+		slog.WarnContext(ctx, "Cannot record the resource histories", "error", err)
+	}
+	if err := c.Watch(ctx, w, runID, summaryInterval); err != nil {
+		return nil, errors.Wrap(err, "cannot start the watcher")
+	}
+	return w, nil
+}
+
+// startProgress shows the live progress table of the resources instead of
+// the logs, if enabled and stdout is an interactive terminal. The logs are
+// written to a file in the meantime. The library mode is excluded, as
+// chainsaw writes its output directly to stdout in that mode. The returned
+// function stops the progress table and restores the logs, and can be called
+// more than once. The returned progress is nil if it's not shown.
+func (t *Tester) startProgress(ctx context.Context, w *cluster.Watcher) (*progress, func()) {
+	if !t.options.Progress || t.options.UseLibraryMode || w == nil || !term.IsTerminal(int(os.Stdout.Fd())) { //nolint:gosec // file descriptors fit in int
+		return nil, func() {}
+	}
+	path := filepath.Join(os.TempDir(), "uptest-"+t.options.RunID+".log")
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		slog.WarnContext(ctx, "Cannot create the log file, not showing the progress", "error", err)
+		return nil, func() {}
+	}
+	slog.InfoContext(ctx, "Showing the progress, the logs are written to the log file", "path", path)
+	restore := logging.SetOutput(f)
+	ctx, cancel := context.WithCancel(ctx)
+	p := newProgress(os.Stdout, w)
+	p.width = func() int {
+		width, _, err := term.GetSize(int(os.Stdout.Fd())) //nolint:gosec // file descriptors fit in int
+		if err != nil {
+			return 0
+		}
+		return width
+	}
+	done := make(chan struct{})
+	go func() {
+		p.run(ctx)
+		close(done)
+	}()
+	var once sync.Once
+	return p, func() {
+		once.Do(func() {
+			cancel()
+			<-done
+			restore()
+			_ = f.Close()
+		})
+	}
+}
+
+// checkStability watches the managed resources for changes for the
+// specified window after they become ready, and returns an error if any of
+// them keeps changing, i.e. it's in an update loop. The changes of the
+// unstable resources are returned as details for the report.
+func checkStability(ctx context.Context, resources []config.Resource, window time.Duration) ([]string, error) {
+	c, err := cluster.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the control plane client")
+	}
+	slog.InfoContext(ctx, "Watching the managed resources for changes", "window", window)
+	results, err := c.CheckStability(ctx, resources, window, stabilityPollInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot watch the managed resources")
+	}
+	var details []string
+	for _, r := range results {
+		if !r.Stable() {
+			details = append(details, r.String())
+		}
+	}
+	if len(details) > 0 {
+		return details, errors.Errorf("%d managed resources kept changing after they became ready", len(details))
+	}
+	return nil, nil
+}
+
+// recordExternalRefs records the external names of the managed resources
+// before they are deleted, so that their external resources can be checked
+// for leaks afterwards.
+func recordExternalRefs(ctx context.Context, resources []config.Resource) ([]cluster.ExternalRef, error) {
+	c, err := cluster.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the control plane client")
+	}
+	refs, err := c.RecordExternalRefs(ctx, resources)
+	return refs, errors.Wrap(err, "cannot record the external names of the managed resources")
+}
+
+// checkLeaks observes the external resources of the deleted managed resources
+// within the timeout and returns an error if any of them still exists, i.e.
+// it's leaked. The leaked resources, and the ones whose existence could not
+// be determined, are returned as details for the report.
+func checkLeaks(ctx context.Context, refs []cluster.ExternalRef, timeout time.Duration) ([]string, error) {
+	c, err := cluster.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the control plane client")
+	}
+	slog.InfoContext(ctx, "Checking whether the external resources of the deleted managed resources still exist", "count", len(refs))
+	results, err := c.CheckLeaks(ctx, refs, timeout, leakCheckPollInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot observe the external resources")
+	}
+	var details []string
+	leaked := 0
+	for _, r := range results {
+		if r.Status == cluster.LeakStatusDeleted {
+			continue
+		}
+		if r.Status == cluster.LeakStatusLeaked {
+			leaked++
+		}
+		details = append(details, r.String())
+	}
+	if leaked > 0 {
+		return details, errors.Errorf("%d external resources still exist after their managed resources were deleted", leaked)
+	}
+	return details, nil
+}
+
+// restartProviders pauses the managed resources and restarts the provider
+// Deployments reconciling them, so that the resources are imported by
+// controllers without any state from the previous steps. The resources are
+// unpaused by the import step after their statuses are cleared.
+func restartProviders(ctx context.Context, resources []config.Resource, timeout time.Duration) error {
+	c, err := cluster.New()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the control plane client")
+	}
+	var kinds []schema.GroupKind
+	seen := map[schema.GroupKind]bool{}
+	for _, r := range resources {
+		if !r.IsManaged() {
+			continue
+		}
+		if err := c.Pause(ctx, r); err != nil {
+			return errors.Wrap(err, "cannot pause the managed resource")
+		}
+		gk := schema.FromAPIVersionAndKind(r.APIVersion, r.Kind).GroupKind()
+		if !seen[gk] {
+			seen[gk] = true
+			kinds = append(kinds, gk)
+		}
+	}
+	deployments, err := c.ProviderDeployments(ctx, kinds)
+	if err != nil {
+		return errors.Wrap(err, "cannot find the provider Deployments")
+	}
+	if len(deployments) == 0 {
+		slog.InfoContext(ctx, "No provider Deployments found to restart")
+		return nil
+	}
+	return errors.Wrap(c.RestartDeployments(ctx, deployments, timeout), "cannot restart the provider Deployments")
+}
+
+// executeSingleTestFile executes the test file within the timeout. The
+// output of chainsaw is written to the log of the specified phase, so that
+// the cleanup does not overwrite the log of the failed delete step.
+func executeSingleTestFile(ctx context.Context, t *Tester, tf, phase string, timeout time.Duration) error {
 	if t.options.UseLibraryMode {
-		return executeSingleTestFileLibraryMode(ctx, t, tf, timeout, resources)
+		return executeSingleTestFileLibraryMode(ctx, t, tf, timeout)
 	}
-	return executeSingleTestFileCLIMode(ctx, t, tf, timeout, resources)
+	return executeSingleTestFileCLIMode(ctx, t, tf, phase, timeout)
 }
 
-func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string, timeout time.Duration, resources []config.Resource) error {
+func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string, timeout time.Duration) error {
 	// Explicitly Set Controller Logger
 	// because of log.SetLogger(...) was never called;
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	ctrl.SetLogger(logr.FromSlogHandler(slog.Default().Handler()))
 
-	log.Println("Loading default configuration...")
+	slog.DebugContext(ctx, "Loading default configuration")
 	configuration, err := kconfig.DefaultConfiguration()
 	if err != nil {
 		return errors.Wrap(err, "failed to load Chainsaw default configuration")
@@ -115,17 +651,18 @@ func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string,
 	configuration.Spec.Execution.Parallel = ptr.To(1)
 	configuration.Spec.Cleanup.SkipDelete = true
 
-	log.Printf("- Using test file: %s\n", configuration.Spec.Discovery.TestFile)
-	log.Printf("- ApplyTimeout %v\n", configuration.Spec.Timeouts.Apply.Duration)
-	log.Printf("- AssertTimeout %v\n", configuration.Spec.Timeouts.Assert.Duration)
-	log.Printf("- CleanupTimeout %v\n", configuration.Spec.Timeouts.Cleanup.Duration)
-	log.Printf("- DeleteTimeout %v\n", configuration.Spec.Timeouts.Delete.Duration)
-	log.Printf("- ErrorTimeout %v\n", configuration.Spec.Timeouts.Error.Duration)
-	log.Printf("- ExecTimeout %v\n", configuration.Spec.Timeouts.Exec.Duration)
-	log.Printf("- Parallel %d\n", *configuration.Spec.Execution.Parallel)
+	slog.DebugContext(ctx, "Loaded default configuration",
+		"testFile", configuration.Spec.Discovery.TestFile,
+		"applyTimeout", configuration.Spec.Timeouts.Apply.Duration,
+		"assertTimeout", configuration.Spec.Timeouts.Assert.Duration,
+		"cleanupTimeout", configuration.Spec.Timeouts.Cleanup.Duration,
+		"deleteTimeout", configuration.Spec.Timeouts.Delete.Duration,
+		"errorTimeout", configuration.Spec.Timeouts.Error.Duration,
+		"execTimeout", configuration.Spec.Timeouts.Exec.Duration,
+		"parallel", *configuration.Spec.Execution.Parallel)
 	color.Init(false, true)
 
-	log.Println("Loading tests...")
+	slog.DebugContext(ctx, "Loading tests")
 	tests, err := discovery.DiscoverTests(tf, nil, false, t.options.Directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to discover test cases")
@@ -134,14 +671,14 @@ func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string,
 	var testToRun []discovery.Test
 	for _, test := range tests {
 		if test.Err != nil {
-			log.Printf("- %s (%s) - (%s)\n", test.Test.Name, test.BasePath, test.Err)
+			slog.WarnContext(ctx, "Cannot load test", "test", test.Test.Name, "path", test.BasePath, "error", test.Err)
 		} else {
-			log.Printf("- %s (%s)\n", test.Test.Name, test.BasePath)
+			slog.DebugContext(ctx, "Loaded test", "test", test.Test.Name, "path", test.BasePath)
 			testToRun = append(testToRun, test)
 		}
 	}
 
-	log.Println("Running tests...")
+	slog.DebugContext(ctx, "Running tests")
 	overrides := clientcmd.ConfigOverrides{}
 	restConfig, err := restutils.DefaultConfig(overrides)
 	if err != nil {
@@ -155,21 +692,11 @@ func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string,
 
 	clock := clock.RealClock{}
 	onFailure := func() {
-		log.Println("Test failed.")
+		slog.ErrorContext(ctx, "Test failed")
 	}
 
 	runner := runner.New(clock, onFailure)
 
-	ticker := time.NewTicker(t.options.LogCollectionInterval)
-	done := make(chan bool)
-	var mutex sync.Mutex
-	defer func() {
-		ticker.Stop()
-		close(done)
-	}()
-
-	go logCollectorLibraryMode(done, ticker, &mutex, resources)
-
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -181,10 +708,7 @@ func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string,
 		return errors.Wrap(err, "test execution failed")
 	}
 
-	log.Println("Tests Summary:")
-	log.Printf("- Passed: %d\n", tc.Passed())
-	log.Printf("- Failed: %d\n", tc.Failed())
-	log.Printf("- Skipped: %d\n", tc.Skipped())
+	slog.InfoContext(ctx, "Tests summary", "passed", tc.Passed(), "failed", tc.Failed(), "skipped", tc.Skipped())
 
 	if tc.Failed() > 0 {
 		return errors.New("some tests failed")
@@ -193,7 +717,7 @@ func executeSingleTestFileLibraryMode(ctx context.Context, t *Tester, tf string,
 	return nil
 }
 
-func executeSingleTestFileCLIMode(ctx context.Context, t *Tester, tf string, timeout time.Duration, resources []config.Resource) error {
+func executeSingleTestFileCLIMode(ctx context.Context, t *Tester, tf, phase string, timeout time.Duration) error {
 	chainsawCommand := fmt.Sprintf(`"${CHAINSAW}" test --test-dir %s --test-file %s --skip-delete --parallel 1 2>&1`,
 		filepath.Clean(filepath.Join(t.options.Directory, caseDirectory)),
 		filepath.Clean(tf))
@@ -201,117 +725,87 @@ func executeSingleTestFileCLIMode(ctx context.Context, t *Tester, tf string, tim
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	out, err := createPhaseLog(t.options.Directory, phase)
+	if err != nil {
+		return err
+	}
+	defer out.Close() //nolint:errcheck // The write errors are checked below.
+
 	cmd := exec.CommandContext(ctx, "bash", "-c", chainsawCommand) // #nosec G204
 	stdout, _ := cmd.StdoutPipe()
 	if err := cmd.Start(); err != nil {
 		return errors.Wrapf(err, "cannot start chainsaw: %s", chainsawCommand)
 	}
 
-	// Start ticker for kubectl command every 30 seconds
-	ticker := time.NewTicker(t.options.LogCollectionInterval)
-	done := make(chan bool)
-	defer func() {
-		ticker.Stop()
-		close(done)
-	}()
-
-	var mutex sync.Mutex
-	go logCollectorCLIMode(done, ticker, &mutex, resources)
-
+	// The output is read to the end even if it cannot be written to the
+	// phase log or scanned, so that chainsaw is not blocked on a full pipe
+	// and is always waited for.
+	var errs []error
 	sc := bufio.NewScanner(stdout)
 	for sc.Scan() {
-		mutex.Lock()
-		log.Println(sc.Text())
-		mutex.Unlock()
+		slog.InfoContext(ctx, sc.Text())
+		if len(errs) > 0 {
+			continue
+		}
+		if _, err := fmt.Fprintln(out, sc.Text()); err != nil {
+			errs = append(errs, errors.Wrapf(err, "cannot write the chainsaw output to %s", out.Name()))
+		}
 	}
 	if sc.Err() != nil {
-		return errors.Wrap(sc.Err(), "cannot scan output")
+		errs = append(errs, errors.Wrap(sc.Err(), "cannot scan output"))
+		_, _ = io.Copy(io.Discard, stdout)
 	}
 	if err := cmd.Wait(); err != nil {
-		return errors.Wrapf(err, "cannot wait for chainsaw: %s", chainsawCommand)
-	}
-
-	return nil
-}
-
-func logCollectorLibraryMode(done chan bool, ticker *time.Ticker, mutex sync.Locker, resources []config.Resource) {
-	logger := logging.NewNopLogger()
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			mutex.Lock()
-
-			kongParser := kong.Must(&trace.Cmd{})
-			kongCtx := &kong.Context{
-				Kong: kongParser,
-			}
-
-			for _, r := range resources {
-				traceCmd := trace.Cmd{
-					Resource: r.KindGroup,
-					Name:     r.Name,
-					Output:   "wide",
-				}
-
-				if r.Namespace != "" {
-					traceCmd.Namespace = r.Namespace
-				}
-
-				if err := traceCmd.Run(kongCtx, logger); err != nil {
-					continue
-				}
-			}
-			mutex.Unlock()
-		}
+		errs = append(errs, errors.Wrapf(err, "cannot wait for chainsaw: %s", chainsawCommand))
 	}
+	return errors.Join(errs...)
 }
 
-func logCollectorCLIMode(done chan bool, ticker *time.Ticker, mutex sync.Locker, resources []config.Resource) {
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			mutex.Lock()
-			for _, r := range resources {
-				// During the setup script is running, the crossplane command
-				// is failing because of the resource not found error.
-				// We do not want to show this error to the user because it
-				// is a noise and temporary one.
-				// The error output was redirected to a file.
-				traceCmdArgs := fmt.Sprintf(`"${CROSSPLANE_CLI}" beta trace %s %s -o wide 2>>/tmp/uptest_crossplane_temp_errors.log`, r.KindGroup, r.Name)
-				if r.Namespace != "" {
-					traceCmdArgs = fmt.Sprintf(`"${CROSSPLANE_CLI}" beta trace %s %s -n %s -o wide 2>>/tmp/uptest_crossplane_temp_errors.log`, r.KindGroup, r.Name, r.Namespace)
-				}
-				traceCmd := exec.Command("bash", "-c", traceCmdArgs) //nolint:gosec // Disabling gosec to allow dynamic shell command execution
-				output, err := traceCmd.CombinedOutput()
-				if err == nil {
-					log.Printf("crossplane trace logs %s\n%s\n", time.Now(), string(output))
-				}
-			}
-			mutex.Unlock()
-		}
+// createPhaseLog creates the file the raw chainsaw output of the phase is
+// written to, under the logs directory of the test directory.
+func createPhaseLog(dir, phase string) (*os.File, error) {
+	dir = filepath.Join(dir, LogsDirectory, phaseLogsDirectory)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil { //nolint:gosec // directory permissions are not critical here
+		return nil, errors.Wrapf(err, "cannot create the phase logs directory %s", dir)
 	}
+	path := filepath.Join(dir, strings.TrimSuffix(phase, filepath.Ext(phase))+".log")
+	f, err := os.Create(filepath.Clean(path))
+	return f, errors.Wrapf(err, "cannot create the phase log %s", path)
 }
 
-func (t *Tester) prepareConfig() (*config.TestCase, []config.Resource, error) { //nolint:gocyclo // TODO: can we break this?
+func (t *Tester) prepareConfig(ctx context.Context) (*config.TestCase, []config.Resource, error) { //nolint:gocyclo // TODO: can we break this?
 	tc := &config.TestCase{
 		Timeout:                  t.options.DefaultTimeout,
 		SetupScriptPath:          t.options.SetupScriptPath,
 		TeardownScriptPath:       t.options.TeardownScriptPath,
 		OnlyCleanUptestResources: t.options.OnlyCleanUptestResources,
+		WaitAllManaged:           t.options.WaitAllManaged,
+		CleanupTimeout:           t.options.CleanupTimeout,
+		RunID:                    t.options.RunID,
+		ImportMode:               t.options.ImportMode,
+		ObserveOnlyTest:          t.options.ObserveOnlyTest,
+		OrderedApply:             t.options.OrderedApply,
 		TestDirectory:            "test-input.yaml",
 	}
 	examples := make([]config.Resource, 0, len(t.manifests))
 
-	rootFound := false
-	for _, m := range t.manifests {
+	objs := make([]*unstructured.Unstructured, len(t.manifests))
+	for i, m := range t.manifests {
+		objs[i] = m.Object
+	}
+	tiers, err := graph.Tiers(objs)
+	if err != nil {
+		slog.WarnContext(ctx, "Cannot resolve the dependencies of the resources, falling back to a single tier", "error", err)
+		tiers = make([]int, len(objs))
+	}
+
+	rootFound, managedFound := false, false
+	for i, m := range t.manifests {
 		obj := m.Object
 		groupVersionKind := obj.GroupVersionKind()
 		apiVersion, kind := groupVersionKind.ToAPIVersionAndKind()
-		kg := strings.ToLower(groupVersionKind.Kind + "." + groupVersionKind.Group)
+		kg := graph.KindGroup(obj)
+		rctx := logging.WithAttrs(ctx, logging.Resource(kg, obj.GetNamespace(), obj.GetName()))
 
 		example := config.Resource{
 			Name:       obj.GetName(),
@@ -322,9 +816,12 @@ func (t *Tester) prepareConfig() (*config.TestCase, []config.Resource, error) {
 			Conditions: t.options.DefaultConditions,
 			APIVersion: apiVersion,
 			Kind:       kind,
+			Tier:       tiers[i],
+		}
+		if example.Category, err = classify(obj); err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot classify %s/%s", kg, obj.GetName())
 		}
 
-		var err error
 		annotations := obj.GetAnnotations()
 		if v, ok := annotations[config.AnnotationKeyTimeout]; ok {
 			d, err := strconv.Atoi(v)
@@ -336,6 +833,22 @@ func (t *Tester) prepareConfig() (*config.TestCase, []config.Resource, error) {
 				tc.Timeout = example.Timeout
 			}
 		}
+		for _, p := range []struct {
+			key     string
+			timeout time.Duration
+			budget  *time.Duration
+		}{
+			{key: config.AnnotationKeyTimeoutApply, timeout: t.options.ApplyTimeout, budget: &tc.ApplyTimeout},
+			{key: config.AnnotationKeyTimeoutUpdate, timeout: t.options.UpdateTimeout, budget: &tc.UpdateTimeout},
+			{key: config.AnnotationKeyTimeoutImport, timeout: t.options.ImportTimeout, budget: &tc.ImportTimeout},
+			{key: config.AnnotationKeyTimeoutDelete, timeout: t.options.DeleteTimeout, budget: &tc.DeleteTimeout},
+		} {
+			d, err := phaseTimeout(annotations, p.key, p.timeout, example.Timeout)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "cannot get the phase timeout of %s/%s", kg, obj.GetName())
+			}
+			*p.budget = max(*p.budget, d)
+		}
 
 		if v, ok := annotations[config.AnnotationKeyConditions]; ok {
 			example.Conditions = strings.Split(v, ",")
@@ -369,31 +882,56 @@ func (t *Tester) prepareConfig() (*config.TestCase, []config.Resource, error) {
 			}
 		}
 
+		if v, ok := annotations[config.AnnotationKeyDriftHook]; ok {
+			example.DriftScriptPath, err = filepath.Abs(filepath.Join(filepath.Dir(m.FilePath), filepath.Clean(v)))
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "cannot find absolute path for drift hook")
+			}
+		}
+
+		if v, ok := annotations[config.AnnotationKeyDriftParameter]; ok {
+			example.DriftPatch, example.DriftAssertions, err = buildDriftPatch(v)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "cannot build the drift patch for %s/%s", kg, obj.GetName())
+			}
+		}
+
 		updateParameter, ok := annotations[config.AnnotationKeyUpdateParameter]
 		if !ok {
 			updateParameter = os.Getenv("UPTEST_UPDATE_PARAMETER")
 		}
 		if updateParameter != "" {
 			example.UpdateParameter = updateParameter
-			var data map[string]interface{}
-			if err := json.Unmarshal([]byte(updateParameter), &data); err != nil {
-				return nil, nil, errors.Wrapf(err, "cannot unmarshal JSON object: %s", updateParameter)
+			example.UpdatePatchType = config.PatchType(annotations[config.AnnotationKeyUpdatePatchType])
+			if example.UpdatePatchType == "" {
+				example.UpdatePatchType = config.PatchTypeMerge
+			}
+			example.UpdatePatch, example.UpdateAssertions, err = buildUpdatePatch(example.UpdatePatchType, annotations[config.AnnotationKeyUpdatePath], updateParameter)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "cannot build the update patch for %s/%s", kg, obj.GetName())
 			}
-			example.UpdateAssertKey, example.UpdateAssertValue = convertToJSONPath(data, "")
 		}
 		disableImport, ok := annotations[config.AnnotationKeyDisableImport]
 		if ok && disableImport == "true" {
 			example.SkipImport = true
 		}
 
+		if annotations[config.AnnotationKeyVerifyOrphan] == "true" {
+			example.VerifyOrphan = true
+		}
+
 		if exampleID, ok := annotations[config.AnnotationKeyExampleID]; ok {
 			if exampleID == strings.ToLower(fmt.Sprintf("%s/%s/%s", strings.Split(groupVersionKind.Group, ".")[0], groupVersionKind.Version, groupVersionKind.Kind)) {
 				if disableImport == "true" {
-					log.Println("Skipping import step because the root resource has disable import annotation")
+					slog.InfoContext(rctx, "Skipping import step because the root resource has disable import annotation")
 					tc.SkipImport = true
 				}
 				if updateParameter == "" {
-					log.Println("Skipping update step because the root resource does not have the update parameter")
+					slog.InfoContext(rctx, "Skipping update step because the root resource does not have the update parameter")
+					tc.SkipUpdate = true
+				}
+				if !example.IsManaged() {
+					slog.InfoContext(rctx, "Skipping update step because the root resource is not a managed resource", "category", example.Category)
 					tc.SkipUpdate = true
 				}
 				example.Root = true
@@ -401,47 +939,161 @@ func (t *Tester) prepareConfig() (*config.TestCase, []config.Resource, error) {
 			}
 		}
 
+		if example.IsManaged() {
+			managedFound = true
+			if example.HasDrift() {
+				tc.DriftTest = true
+			}
+		}
 		examples = append(examples, example)
 	}
 
+	if !managedFound {
+		slog.InfoContext(ctx, "Skipping import step because there are no managed resources")
+		tc.SkipImport = true
+		if tc.ObserveOnlyTest {
+			slog.InfoContext(ctx, "Skipping observe-only step because there are no managed resources")
+			tc.ObserveOnlyTest = false
+		}
+	}
 	if !rootFound {
-		log.Println("Skipping update step because the root resource does not exist")
+		slog.InfoContext(ctx, "Skipping update step because the root resource does not exist")
 		tc.SkipUpdate = true
 	}
 	if t.options.SkipUpdate {
-		log.Println("Skipping update step because the skip-update option is set to true")
+		slog.InfoContext(ctx, "Skipping update step because the skip-update option is set to true")
 		tc.SkipUpdate = true
 	}
 	if t.options.SkipImport {
-		log.Println("Skipping import step because the skip-import option is set to true")
+		slog.InfoContext(ctx, "Skipping import step because the skip-import option is set to true")
 		tc.SkipImport = true
 	}
 	if t.options.SkipWebhookCheck {
-		log.Println("Skipping webhook check because the skip-webhook-check option is set to true")
+		slog.InfoContext(ctx, "Skipping webhook check because the skip-webhook-check option is set to true")
 		tc.SkipWebhookCheck = true
 	}
 
+	tc.SetDefaultPhaseTimeouts()
 	return tc, examples, nil
 }
 
-func (t *Tester) writeChainsawFiles() ([]config.Resource, time.Duration, error) {
-	tc, examples, err := t.prepareConfig()
+// phaseTimeout returns the time budget of the resource in a step, which is
+// set by the annotation with the specified key in seconds, or by the timeout
+// of the step if the annotation is not set, or is the timeout of the
+// resource if neither is set.
+func phaseTimeout(annotations map[string]string, key string, phase, resource time.Duration) (time.Duration, error) {
+	if v, ok := annotations[key]; ok {
+		d, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, errors.Wrapf(err, "value of annotation %s is not valid", key)
+		}
+		return time.Duration(d) * time.Second, nil
+	}
+	if phase > 0 {
+		return phase, nil
+	}
+	return resource, nil
+}
+
+// phaseTimeouts returns the time budget of each test file, which is
+// independent of the time the previous ones took, so that a slow apply step
+// does not leave the delete step without time to delete the resources. The
+// steps without their own budget get the timeout of the test case. The
+// delete step also waits for the managed resources of the run to be cleaned
+// up, and the leak check shares the cleanup timeout between all of the
+// deleted managed resources.
+func phaseTimeouts(tc *config.TestCase) map[string]time.Duration {
+	return map[string]time.Duration{
+		"00-apply.yaml":   tc.ApplyTimeout,
+		"00-observe.yaml": tc.Timeout,
+		"00-drift.yaml":   tc.Timeout,
+		"01-update.yaml":  tc.UpdateTimeout,
+		"02-import.yaml":  tc.ImportTimeout,
+		"03-delete.yaml":  tc.DeleteTimeout + tc.CleanupTimeout,
+		leakCheckPhase:    tc.CleanupTimeout,
+		teardownFile:      tc.DeleteTimeout,
+	}
+}
+
+func (t *Tester) writeChainsawFiles(ctx context.Context) ([]config.Resource, map[string]time.Duration, error) {
+	tc, examples, err := t.prepareConfig(ctx)
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "cannot build examples config")
+		return nil, nil, errors.Wrap(err, "cannot build examples config")
 	}
 
 	files, err := templates.Render(tc, examples, t.options.SkipDelete)
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "cannot render chainsaw templates")
+		return nil, nil, errors.Wrap(err, "cannot render chainsaw templates")
 	}
 
 	for k, v := range files {
 		if err := os.WriteFile(filepath.Join(filepath.Join(t.options.Directory, caseDirectory), k), []byte(v), fs.ModePerm); err != nil {
-			return nil, 0, errors.Wrapf(err, "cannot write file %q", k)
+			return nil, nil, errors.Wrapf(err, "cannot write file %q", k)
+		}
+	}
+
+	if tc.OrderedApply {
+		if err := writeTierFiles(examples, t.options.Directory); err != nil {
+			return nil, nil, errors.Wrap(err, "cannot write tier input files")
+		}
+	}
+
+	return examples, phaseTimeouts(tc), nil
+}
+
+// writeTierFiles writes the manifests of each dependency tier to a separate
+// input file, so that the tiers can be applied one by one.
+func writeTierFiles(resources []config.Resource, directory string) error {
+	for _, tier := range templates.ApplyTiers(resources) {
+		var b strings.Builder
+		for _, r := range tier.Resources {
+			b.WriteString("---\n" + r.YAML + "\n")
+		}
+		if err := os.WriteFile(filepath.Join(directory, caseDirectory, tier.File), []byte(b.String()), fs.ModePerm); err != nil {
+			return errors.Wrapf(err, "cannot write file %q", tier.File)
 		}
 	}
+	return nil
+}
 
-	return examples, tc.Timeout, nil
+// readTestFile reads the manifests from the test input file of the test case
+// in the directory.
+func readTestFile(directory string) ([]config.Manifest, error) {
+	path := filepath.Join(directory, caseDirectory, "test-input.yaml")
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read %s", path)
+	}
+	var res []config.Manifest
+	decoder := kyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 1024)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.Wrapf(err, "cannot decode %s", path)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		y, err := yaml.Marshal(u)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot marshal manifest for \"%s/%s\"", u.GetKind(), u.GetName())
+		}
+		res = append(res, config.Manifest{FilePath: path, Object: u, YAML: string(y)})
+	}
+	return res, nil
+}
+
+// runIDOf returns the run ID the manifests are labelled with, if any.
+func runIDOf(ms []config.Manifest) string {
+	for _, m := range ms {
+		if id := m.Object.GetLabels()[config.LabelKeyRunID]; id != "" {
+			return id
+		}
+	}
+	return ""
 }
 
 func writeTestFile(manifests []config.Manifest, directory string) error {
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestPhaseFile(t *testing.T) {
+	type want struct {
+		file string
+		err  error
+	}
+	cases := map[string]struct {
+		phase string
+		want  want
+	}{
+		"StepName": {
+			phase: "import",
+			want:  want{file: "02-import.yaml"},
+		},
+		"FileName": {
+			phase: "03-delete.yaml",
+			want:  want{file: "03-delete.yaml"},
+		},
+		"Unknown": {
+			phase: "create",
+			want:  want{err: errors.New(`unknown phase "create", expected one of apply, observe, drift, update, import, delete`)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file, err := phaseFile(tc.phase)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("phaseFile(%q): -want error, +got error:\n%s", tc.phase, diff)
+			}
+			if diff := cmp.Diff(tc.want.file, file); diff != "" {
+				t.Errorf("phaseFile(%q): -want, +got:\n%s", tc.phase, diff)
+			}
+		})
+	}
+}
+
+func TestPhaseTimeout(t *testing.T) {
+	type args struct {
+		annotations map[string]string
+		phase       time.Duration
+		resource    time.Duration
+	}
+	type want struct {
+		timeout time.Duration
+		err     error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Annotation": {
+			args: args{
+				annotations: map[string]string{config.AnnotationKeyTimeoutDelete: "1800"},
+				phase:       10 * time.Minute,
+				resource:    20 * time.Minute,
+			},
+			want: want{timeout: 30 * time.Minute},
+		},
+		"PhaseTimeout": {
+			args: args{phase: 10 * time.Minute, resource: 20 * time.Minute},
+			want: want{timeout: 10 * time.Minute},
+		},
+		"ResourceTimeout": {
+			args: args{resource: 20 * time.Minute},
+			want: want{timeout: 20 * time.Minute},
+		},
+		"InvalidAnnotation": {
+			args: args{annotations: map[string]string{config.AnnotationKeyTimeoutDelete: "30m"}},
+			want: want{err: errors.Wrap(errors.New(`strconv.Atoi: parsing "30m": invalid syntax`), "value of annotation uptest.upbound.io/timeout-delete is not valid")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := phaseTimeout(tc.args.annotations, config.AnnotationKeyTimeoutDelete, tc.args.phase, tc.args.resource)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("phaseTimeout(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.timeout, got); diff != "" {
+				t.Errorf("phaseTimeout(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPhaseTimeouts(t *testing.T) {
+	tc := &config.TestCase{
+		Timeout:        20 * time.Minute,
+		ApplyTimeout:   30 * time.Minute,
+		DeleteTimeout:  10 * time.Minute,
+		CleanupTimeout: 5 * time.Minute,
+	}
+	tc.SetDefaultPhaseTimeouts()
+	want := map[string]time.Duration{
+		"00-apply.yaml":   30 * time.Minute,
+		"00-observe.yaml": 20 * time.Minute,
+		"00-drift.yaml":   20 * time.Minute,
+		"01-update.yaml":  20 * time.Minute,
+		"02-import.yaml":  20 * time.Minute,
+		"03-delete.yaml":  15 * time.Minute,
+		leakCheckPhase:    5 * time.Minute,
+		teardownFile:      10 * time.Minute,
+	}
+	if diff := cmp.Diff(want, phaseTimeouts(tc)); diff != "" {
+		t.Errorf("phaseTimeouts(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReadTestFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, caseDirectory), os.ModePerm); err != nil {
+		t.Fatalf("cannot create the case directory: %v", err)
+	}
+	input := `---
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: example
+  labels:
+    uptest.upbound.io/run-id: abc
+spec:
+  forProvider:
+    region: us-west-1
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`
+	if err := os.WriteFile(filepath.Join(dir, caseDirectory, "test-input.yaml"), []byte(input), 0o600); err != nil {
+		t.Fatalf("cannot write the test input file: %v", err)
+	}
+
+	ms, err := readTestFile(dir)
+	if err != nil {
+		t.Fatalf("readTestFile(...): %v", err)
+	}
+	got := make([]string, 0, len(ms))
+	for _, m := range ms {
+		got = append(got, m.Object.GetKind()+"/"+m.Object.GetName())
+	}
+	if diff := cmp.Diff([]string{"Bucket/example", "Namespace/test"}, got); diff != "" {
+		t.Errorf("readTestFile(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("abc", runIDOf(ms)); diff != "" {
+		t.Errorf("runIDOf(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("", runIDOf([]config.Manifest{ms[1]})); diff != "" {
+		t.Errorf("runIDOf(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+const (
+	defaultUpdatePath = "spec.forProvider"
+
+	atProviderPath = ".status.atProvider"
+)
+
+// jsonPatchOperation is a single operation of a JSON patch document.
+type jsonPatchOperation struct {
+	Op   string `json:"op"`
+	From string `json:"from,omitempty"`
+	Path string `json:"path"`
+	// Value is kept as is, so that it's only omitted if the operation does
+	// not have it, and a null or an empty value is not dropped.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// buildUpdatePatch builds the patch document applied to the resource during
+// the update step and the assertions that verify the patch was reconciled.
+// The update parameter is relative to the specified dot separated path. For
+// merge and strategic patches, it's the value of the field at path. For JSON
+// patches, it's a list of operations whose paths are relative to path.
+func buildUpdatePatch(patchType config.PatchType, path, parameter string) (string, []config.UpdateAssertion, error) {
+	if path == "" {
+		path = defaultUpdatePath
+	}
+	segments := strings.Split(path, ".")
+	assertBase := "." + path
+	// spec.forProvider is reflected in status.atProvider once the resource
+	// is reconciled. The other paths, e.g. spec.initProvider, which is not
+	// reflected once the resource is created, are asserted as they are.
+	if path == defaultUpdatePath {
+		assertBase = atProviderPath
+	}
+
+	var patch string
+	var assertions []config.UpdateAssertion
+	switch patchType {
+	case "", config.PatchTypeMerge, config.PatchTypeStrategic:
+		var data interface{}
+		if err := json.Unmarshal([]byte(parameter), &data); err != nil {
+			return "", nil, errors.Wrapf(err, "cannot unmarshal JSON object: %s", parameter)
+		}
+		patch = parameter
+		for i := len(segments) - 1; i >= 0; i-- {
+			patch = fmt.Sprintf(`{"%s":%s}`, segments[i], patch)
+		}
+		if m, ok := data.(map[string]interface{}); ok {
+			k, v := convertToJSONPath(m, "")
+			assertions = append(assertions, config.UpdateAssertion{Path: assertBase + k, Value: v})
+		} else {
+			assertions = append(assertions, config.UpdateAssertion{Path: assertBase, Value: assertionValue(data)})
+		}
+	case config.PatchTypeJSON:
+		var ops []jsonPatchOperation
+		if err := json.Unmarshal([]byte(parameter), &ops); err != nil {
+			return "", nil, errors.Wrapf(err, "cannot unmarshal JSON patch: %s", parameter)
+		}
+		prefix := "/" + strings.Join(segments, "/")
+		for i, op := range ops {
+			a, err := jsonPatchAssertions(assertBase, op)
+			if err != nil {
+				return "", nil, err
+			}
+			assertions = append(assertions, a...)
+			ops[i].Path = prefix + op.Path
+			if op.From != "" {
+				ops[i].From = prefix + op.From
+			}
+		}
+		b, err := json.Marshal(ops)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "cannot marshal JSON patch")
+		}
+		patch = string(b)
+	default:
+		return "", nil, errors.Errorf("unknown update patch type %q, must be one of %q, %q or %q", patchType, config.PatchTypeMerge, config.PatchTypeJSON, config.PatchTypeStrategic)
+	}
+	return patch, assertions, nil
+}
+
+// buildDriftPatch builds the merge patch applied to the drift twin of a
+// resource from the drift parameter, which is merged into spec.forProvider,
+// and the assertions that verify the drifted fields are reconciled back to
+// their desired values.
+func buildDriftPatch(parameter string) (string, []config.DriftAssertion, error) {
+	patch, updateAssertions, err := buildUpdatePatch(config.PatchTypeMerge, defaultUpdatePath, parameter)
+	if err != nil {
+		return "", nil, err
+	}
+	assertions := make([]config.DriftAssertion, 0, len(updateAssertions))
+	for _, a := range updateAssertions {
+		assertions = append(assertions, config.DriftAssertion{
+			Path:        a.Path,
+			DesiredPath: "." + defaultUpdatePath + strings.TrimPrefix(a.Path, atProviderPath),
+			Value:       a.Value,
+		})
+	}
+	return patch, assertions, nil
+}
+
+// jsonPatchAssertions returns the assertions for a JSON patch operation.
+// Additions and replacements are asserted by their values, removals of
+// fields, and null values, by the absence of the field. Removals of list
+// elements are asserted by comparing the observed list with the desired one,
+// as the following elements move to the removed index. Operations that do
+// not have a predictable outcome, such as appending to a list, are not
+// asserted.
+func jsonPatchAssertions(base string, op jsonPatchOperation) ([]config.UpdateAssertion, error) {
+	if strings.HasSuffix(op.Path, "/-") {
+		return nil, nil
+	}
+	p := base + jsonPointerToJSONPath(op.Path)
+	switch op.Op {
+	case "add", "replace":
+		var value interface{}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, errors.Wrapf(err, "cannot unmarshal the value of the %s operation at %s", op.Op, op.Path)
+		}
+		if value == nil {
+			return []config.UpdateAssertion{{Path: p, Absent: true}}, nil
+		}
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			k, v := convertToJSONPath(m, "")
+			return []config.UpdateAssertion{{Path: p + k, Value: v}}, nil
+		}
+		return []config.UpdateAssertion{{Path: p, Value: assertionValue(value)}}, nil
+	case "remove":
+		i := strings.LastIndex(op.Path, "/")
+		if _, err := strconv.Atoi(op.Path[i+1:]); err != nil {
+			return []config.UpdateAssertion{{Path: p, Absent: true}}, nil
+		}
+		// The list is only observed in status.atProvider if the patch is
+		// applied to spec.forProvider. Otherwise, the patched list is the
+		// list itself.
+		if base != atProviderPath {
+			return nil, nil
+		}
+		list := jsonPointerToJSONPath(op.Path[:i])
+		return []config.UpdateAssertion{{Path: base + list, DesiredPath: "." + defaultUpdatePath + list}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// jsonPointerToJSONPath converts a JSON pointer, such as /tags/0, to the
+// equivalent kubectl JSONPath expression, such as .tags[0].
+func jsonPointerToJSONPath(pointer string) string {
+	var b strings.Builder
+	for _, s := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if s == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(s); err == nil {
+			b.WriteString("[" + s + "]")
+			continue
+		}
+		s = strings.NewReplacer("~1", "/", "~0", "~", ".", `\.`).Replace(s)
+		b.WriteString("." + s)
+	}
+	return b.String()
+}
+
+// assertionValue formats the value as it's printed by kubectl JSONPath
+// output.
+func assertionValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
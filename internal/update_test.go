@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane/uptest/v2/internal/config"
+)
+
+func TestBuildUpdatePatch(t *testing.T) {
+	type args struct {
+		patchType config.PatchType
+		path      string
+		parameter string
+	}
+	type want struct {
+		patch      string
+		assertions []config.UpdateAssertion
+		err        bool
+	}
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"DefaultMergePatch": {
+			args: args{
+				parameter: `{"tags":{"key":"value"}}`,
+			},
+			want: want{
+				patch: `{"spec":{"forProvider":{"tags":{"key":"value"}}}}`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".status.atProvider.tags.key", Value: "value"},
+				},
+			},
+		},
+		"InitProvider": {
+			args: args{
+				patchType: config.PatchTypeMerge,
+				path:      "spec.initProvider",
+				parameter: `{"description":"updated"}`,
+			},
+			want: want{
+				patch: `{"spec":{"initProvider":{"description":"updated"}}}`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".spec.initProvider.description", Value: "updated"},
+				},
+			},
+		},
+		"MergePatchQuotes": {
+			args: args{
+				parameter: `{"description":"it's updated"}`,
+			},
+			want: want{
+				patch: `{"spec":{"forProvider":{"description":"it's updated"}}}`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".status.atProvider.description", Value: "it's updated"},
+				},
+			},
+		},
+		"MergePatchManagementPolicies": {
+			args: args{
+				patchType: config.PatchTypeMerge,
+				path:      "spec.managementPolicies",
+				parameter: `["Observe","Update"]`,
+			},
+			want: want{
+				patch: `{"spec":{"managementPolicies":["Observe","Update"]}}`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".spec.managementPolicies", Value: `["Observe","Update"]`},
+				},
+			},
+		},
+		"StrategicPatch": {
+			args: args{
+				patchType: config.PatchTypeStrategic,
+				path:      "data",
+				parameter: `{"key":"value"}`,
+			},
+			want: want{
+				patch: `{"data":{"key":"value"}}`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".data.key", Value: "value"},
+				},
+			},
+		},
+		"JSONPatch": {
+			args: args{
+				patchType: config.PatchTypeJSON,
+				parameter: `[{"op":"remove","path":"/tags/1"},{"op":"replace","path":"/description","value":"updated"},{"op":"add","path":"/tags/-","value":"new"}]`,
+			},
+			want: want{
+				patch: `[{"op":"remove","path":"/spec/forProvider/tags/1"},{"op":"replace","path":"/spec/forProvider/description","value":"updated"},{"op":"add","path":"/spec/forProvider/tags/-","value":"new"}]`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".status.atProvider.tags", DesiredPath: ".spec.forProvider.tags"},
+					{Path: ".status.atProvider.description", Value: "updated"},
+				},
+			},
+		},
+		"JSONPatchEmptyValues": {
+			args: args{
+				patchType: config.PatchTypeJSON,
+				parameter: `[{"op":"replace","path":"/description","value":""},{"op":"add","path":"/tags","value":{}},{"op":"replace","path":"/count","value":null}]`,
+			},
+			want: want{
+				patch: `[{"op":"replace","path":"/spec/forProvider/description","value":""},{"op":"add","path":"/spec/forProvider/tags","value":{}},{"op":"replace","path":"/spec/forProvider/count","value":null}]`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".status.atProvider.description", Value: ""},
+					{Path: ".status.atProvider.tags", Value: "{}"},
+					{Path: ".status.atProvider.count", Absent: true},
+				},
+			},
+		},
+		"JSONPatchRemoveField": {
+			args: args{
+				patchType: config.PatchTypeJSON,
+				parameter: `[{"op":"remove","path":"/tags/key"}]`,
+			},
+			want: want{
+				patch: `[{"op":"remove","path":"/spec/forProvider/tags/key"}]`,
+				assertions: []config.UpdateAssertion{
+					{Path: ".status.atProvider.tags.key", Absent: true},
+				},
+			},
+		},
+		"InvalidJSONPatch": {
+			args: args{
+				patchType: config.PatchTypeJSON,
+				parameter: `{"description":"updated"}`,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"UnknownPatchType": {
+			args: args{
+				patchType: "apply",
+				parameter: `{"description":"updated"}`,
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch, assertions, err := buildUpdatePatch(tc.args.patchType, tc.args.path, tc.args.parameter)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("buildUpdatePatch(...): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.patch, patch); diff != "" {
+				t.Errorf("buildUpdatePatch(...): -want patch, +got patch:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.assertions, assertions, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("buildUpdatePatch(...): -want assertions, +got assertions:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildDriftPatch(t *testing.T) {
+	type want struct {
+		patch      string
+		assertions []config.DriftAssertion
+		err        bool
+	}
+	tests := map[string]struct {
+		parameter string
+		want      want
+	}{
+		"NestedField": {
+			parameter: `{"tags":{"drift":"true"}}`,
+			want: want{
+				patch: `{"spec":{"forProvider":{"tags":{"drift":"true"}}}}`,
+				assertions: []config.DriftAssertion{
+					{Path: ".status.atProvider.tags.drift", DesiredPath: ".spec.forProvider.tags.drift", Value: "true"},
+				},
+			},
+		},
+		"InvalidParameter": {
+			parameter: `{"tags":`,
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch, assertions, err := buildDriftPatch(tc.parameter)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("buildDriftPatch(...): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.patch, patch); diff != "" {
+				t.Errorf("buildDriftPatch(...): -want patch, +got patch:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.assertions, assertions, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("buildDriftPatch(...): -want assertions, +got assertions:\n%s", diff)
+			}
+		})
+	}
+}
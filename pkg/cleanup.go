@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package pkg
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	"github.com/crossplane/uptest/v2/internal/cluster"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+// Cleanup deletes the objects left behind by the uptest run with the
+// specified run ID, and waits until they are gone.
+func Cleanup(ctx context.Context, runID string, timeout time.Duration) error {
+	ctx = logging.WithAttrs(ctx, logging.KeyRunID, runID)
+	c, err := cluster.New()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the control plane client")
+	}
+	stages, err := c.RunObjects(ctx, runID)
+	if err != nil {
+		return errors.Wrapf(err, "cannot find the objects of run %s", runID)
+	}
+	n := 0
+	for _, objs := range stages {
+		n += len(objs)
+	}
+	slog.InfoContext(ctx, "Found the objects of the run", "count", n)
+	return errors.Wrapf(c.DeleteStages(ctx, stages, timeout), "cannot delete the objects of run %s", runID)
+}
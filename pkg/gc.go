@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: CC0-1.0
+
+package pkg
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	"github.com/crossplane/uptest/v2/internal/cluster"
+	"github.com/crossplane/uptest/v2/internal/logging"
+)
+
+// GC deletes the managed resources left behind by uptest runs that are
+// selected by the options, in dependency order, and waits until they are
+// gone. If dryRun is true, only the deletion plan is printed.
+func GC(ctx context.Context, opts cluster.GCOptions, dryRun bool, timeout time.Duration) error {
+	c, err := cluster.New()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the control plane client")
+	}
+	objs, err := c.Leftovers(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "cannot find the managed resources left behind")
+	}
+	if len(objs) == 0 {
+		slog.InfoContext(ctx, "No managed resources left behind")
+		return nil
+	}
+	stages := cluster.DeletionStages(ctx, objs)
+	slog.InfoContext(ctx, "Deletion plan of the managed resources", "count", len(objs), "stages", len(stages))
+	for i, s := range stages {
+		for _, o := range s {
+			slog.InfoContext(ctx, "Planned deletion", "stage", i, logging.Resource(strings.ToLower(o.GroupVersionKind().GroupKind().String()), o.GetNamespace(), o.GetName()),
+				"age", time.Since(o.GetCreationTimestamp().Time).Round(time.Second))
+		}
+	}
+	if dryRun {
+		return nil
+	}
+	if err := c.DeleteStages(ctx, stages, timeout); err != nil {
+		return errors.Wrap(err, "cannot delete the managed resources")
+	}
+	slog.InfoContext(ctx, "Deleted the managed resources", "count", len(objs))
+	return nil
+}
@@ -7,13 +7,14 @@ package pkg
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 
 	"github.com/crossplane/uptest/v2/internal"
 	"github.com/crossplane/uptest/v2/internal/config"
+	"github.com/crossplane/uptest/v2/internal/logging"
 )
 
 // RunTest runs the specified automated test.
@@ -24,18 +25,39 @@ func RunTest(o *config.AutomatedTest) error {
 }
 
 // RunTestContext runs the specified automated test, respecting context
-// cancellation.
-func RunTestContext(ctx context.Context, o *config.AutomatedTest) error {
+// cancellation. The test directory is removed at the end of the run, unless
+// the test fails or KeepArtifacts is set, so that the rendered test case can
+// be inspected, run again with RunRenderedContext or resumed by setting
+// Resume.
+func RunTestContext(ctx context.Context, o *config.AutomatedTest) (err error) {
 	if !o.RenderOnly {
 		defer func() {
-			if err := os.RemoveAll(o.Directory); err != nil {
-				log.Printf("Cannot clean the test directory: %s\n", err.Error())
+			if err != nil || o.KeepArtifacts {
+				slog.InfoContext(ctx, "Keeping the test directory", "directory", o.Directory)
+				return
+			}
+			if cerr := cleanTestDirectory(o.Directory); cerr != nil {
+				slog.WarnContext(ctx, "Cannot clean the test directory", "error", cerr)
 			}
 		}()
 	}
 
+	if o.Resume && !o.RenderOnly {
+		resumed, err := internal.NewTester(nil, o).Resume(ctx)
+		if resumed || err != nil {
+			return errors.Wrap(err, "cannot resume the tests")
+		}
+		slog.InfoContext(ctx, "No run to resume found, starting a new run", "directory", o.Directory)
+	}
+
+	if o.RunID == "" {
+		o.RunID = internal.NewRunID()
+	}
+	ctx = logging.WithAttrs(ctx, logging.KeyRunID, o.RunID)
+	slog.InfoContext(ctx, "Starting the run")
+
 	// Read examples and inject data source values to manifests
-	manifests, err := internal.NewPreparer(o.ManifestPaths, internal.WithDataSource(o.DataSourcePath), internal.WithTestDirectory(o.Directory)).PrepareManifests()
+	manifests, err := internal.NewPreparer(o.ManifestPaths, internal.WithDataSource(o.DataSourcePath), internal.WithTestDirectory(o.Directory), internal.WithRunID(o.RunID)).PrepareManifests()
 	if err != nil {
 		return errors.Wrap(err, "cannot prepare manifests")
 	}
@@ -48,6 +70,21 @@ func RunTestContext(ctx context.Context, o *config.AutomatedTest) error {
 	return nil
 }
 
+// RunRenderedContext runs the chainsaw test case already rendered in the test
+// directory of the specified automated test, or only its specified phase,
+// against the current control plane without preparing the manifests or
+// rendering the test files again. All phases are run if phase is empty. The
+// test directory is kept.
+func RunRenderedContext(ctx context.Context, o *config.AutomatedTest, phase string) error {
+	return errors.Wrap(internal.NewTester(nil, o).ExecuteRendered(ctx, phase), "cannot execute the rendered tests")
+}
+
+// cleanTestDirectory removes the test directory of a passed run, including
+// its logs, which are only kept to debug a failed run.
+func cleanTestDirectory(dir string) error {
+	return errors.Wrapf(os.RemoveAll(dir), "cannot remove directory %s", dir)
+}
+
 // NewAutomatedTestBuilder returns a Builder for AutomatedTest object
 func NewAutomatedTestBuilder() *config.Builder {
 	return config.NewBuilder()